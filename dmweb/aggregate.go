@@ -0,0 +1,55 @@
+package dmweb
+
+// TagStats are summary statistics of the history of a tag, see
+// DataTag.Aggregate. First and Last are the values of the first and last
+// points in history order. All fields are zero when Count is 0.
+type TagStats struct {
+	Min   float64
+	Max   float64
+	Avg   float64
+	First float64
+	Last  float64
+	Count int
+}
+
+// aggregate returns the statistics of points, skipping points whose
+// quality is known and not good unless all is set.
+func aggregate(points []HistoryPoint, all bool) TagStats {
+	var s TagStats
+	var sum float64
+	for _, p := range points {
+		if !all && p.Quality != "" && !p.Quality.IsGood() {
+			continue
+		}
+		if s.Count == 0 {
+			s.Min, s.Max, s.First = p.Value, p.Value, p.Value
+		}
+		if p.Value < s.Min {
+			s.Min = p.Value
+		}
+		if p.Value > s.Max {
+			s.Max = p.Value
+		}
+		s.Last = p.Value
+		sum += p.Value
+		s.Count++
+	}
+	if s.Count > 0 {
+		s.Avg = sum / float64(s.Count)
+	}
+	return s
+}
+
+// Aggregate returns the minimum, maximum, average, first and last value
+// of the history of the tag. Points of bad, uncertain or other non-good
+// quality are ignored; points without a quality are included, as the
+// DataMailbox doesn't always report one. See AggregateAll.
+func (t *DataTag) Aggregate() TagStats {
+	return aggregate(t.History, false)
+}
+
+// AggregateAll is like Aggregate, but includes the points of any
+// quality.
+func (t *DataTag) AggregateAll() TagStats {
+	return aggregate(t.History, true)
+}
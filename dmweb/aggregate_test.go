@@ -0,0 +1,23 @@
+package dmweb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregate(t *testing.T) {
+	tag := &DataTag{History: []HistoryPoint{
+		{Value: 4, Quality: QualityGood},
+		{Value: 100, Quality: QualityBad},
+		{Value: 1},
+		{Value: -50, Quality: QualityUncertain},
+		{Value: 7, Quality: QualityInitialGood},
+	}}
+	assert.Equal(t, TagStats{Min: 1, Max: 7, Avg: 4, First: 4, Last: 7, Count: 3}, tag.Aggregate())
+	assert.Equal(t, TagStats{Min: -50, Max: 100, Avg: 12.4, First: 4, Last: 7, Count: 5}, tag.AggregateAll())
+
+	assert.Equal(t, TagStats{}, (&DataTag{}).Aggregate())
+	tag = &DataTag{History: []HistoryPoint{{Value: 3, Quality: QualityBad}}}
+	assert.Equal(t, TagStats{}, tag.Aggregate())
+}
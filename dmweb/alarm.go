@@ -0,0 +1,48 @@
+package dmweb
+
+import "strings"
+
+// AlarmState is an alarm state of the eWON alarm history.
+type AlarmState string
+
+// Alarm states of the eWON alarm history.
+const (
+	AlarmStateNone         AlarmState = "NONE"
+	AlarmStateAlarm        AlarmState = "ALM"
+	AlarmStateAcknowledged AlarmState = "ACK"
+	AlarmStateReturned     AlarmState = "RTN"
+	AlarmStateEnded        AlarmState = "END"
+)
+
+// alarmStates maps the uppercased spellings of the alarm states to their
+// constants.
+var alarmStates = map[string]AlarmState{
+	"NONE":         AlarmStateNone,
+	"ALM":          AlarmStateAlarm,
+	"ALARM":        AlarmStateAlarm,
+	"ACK":          AlarmStateAcknowledged,
+	"ACKNOWLEDGED": AlarmStateAcknowledged,
+	"RTN":          AlarmStateReturned,
+	"RETURNED":     AlarmStateReturned,
+	"END":          AlarmStateEnded,
+	"ENDED":        AlarmStateEnded,
+}
+
+// ParseAlarmHint returns the alarm state named by the first word of an
+// alarm hint, like "ALM" or "ack: pressure too high", ignoring case and
+// surrounding spaces, or "" when the first word isn't an alarm state.
+//
+// The alarm hint of a tag is static text configured on the eWON, not a
+// live status, and DMWeb documents no convention for it: the result only
+// means something for installations whose hints follow this one, and
+// never tells whether the tag is currently in alarm. "Alarm when
+// pressure > 5 bar" parses as AlarmStateAlarm all the same.
+func ParseAlarmHint(hint string) AlarmState {
+	word := strings.TrimSpace(hint)
+	if i := strings.IndexFunc(word, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	}); i >= 0 {
+		word = word[:i]
+	}
+	return alarmStates[strings.ToUpper(word)]
+}
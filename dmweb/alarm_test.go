@@ -0,0 +1,24 @@
+package dmweb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAlarmHint(t *testing.T) {
+	for hint, want := range map[string]AlarmState{
+		"ALM":                    AlarmStateAlarm,
+		"alm":                    AlarmStateAlarm,
+		" Alarm: level too high": AlarmStateAlarm,
+		"ACK - pump 2":           AlarmStateAcknowledged,
+		"RTN":                    AlarmStateReturned,
+		"ended":                  AlarmStateEnded,
+		"none":                   AlarmStateNone,
+		"":                       "",
+		"Pressure too high":      "",
+		"ALMOST":                 "",
+	} {
+		assert.Equal(t, want, ParseAlarmHint(hint), hint)
+	}
+}
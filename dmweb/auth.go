@@ -0,0 +1,137 @@
+package dmweb
+
+import (
+	"context"
+	"net/url"
+)
+
+// authStrategy adds the credentials of a client to the parameters of a
+// request.
+type authStrategy interface {
+	authenticate(ctx context.Context, c *Client, v url.Values) error
+	mode() AuthMode
+}
+
+// AuthMode is the way a client authenticates, see Client.AuthMode.
+type AuthMode string
+
+// Authentication modes.
+const (
+	// AuthPassword authenticates with a username and password, see New.
+	AuthPassword AuthMode = "password"
+	// AuthToken authenticates with a Talk2M token, see NewWithToken.
+	AuthToken AuthMode = "token"
+	// AuthProvider authenticates with the credentials of a
+	// CredentialProvider, see NewWithCredentialProvider.
+	AuthProvider AuthMode = "provider"
+)
+
+// AuthMode returns the way the client authenticates, e.g. to log it or
+// to refresh tokens only for token-based clients.
+func (c *Client) AuthMode() AuthMode {
+	return c.authStrategy().mode()
+}
+
+// passwordAuth authenticates with the account's username and password.
+type passwordAuth struct{}
+
+func (passwordAuth) mode() AuthMode { return AuthPassword }
+
+func (passwordAuth) authenticate(ctx context.Context, c *Client, v url.Values) error {
+	v.Add("t2maccount", c.AccountID)
+	v.Add("t2musername", c.Username)
+	v.Add("t2mpassword", c.Password)
+	v.Add("t2mdevid", c.DevID)
+	return nil
+}
+
+// tokenAuth authenticates with a Talk2M token.
+type tokenAuth struct{}
+
+func (tokenAuth) mode() AuthMode { return AuthToken }
+
+func (tokenAuth) authenticate(ctx context.Context, c *Client, v url.Values) error {
+	v.Add("t2maccount", c.AccountID)
+	v.Add("t2mtoken", c.Token)
+	v.Add("t2mdevid", c.DevID)
+	return nil
+}
+
+// Credentials are the credentials of a Talk2M account. Either Token or
+// Username and Password are set.
+type Credentials struct {
+	AccountID string
+	Username  string
+	Password  string
+	Token     string
+	DevID     string
+}
+
+// CredentialProvider provides the credentials of every request, see
+// WithCredentialProvider. Credentials is called with the context of the
+// request, so it can respect its cancellation.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialProviderFunc adapts a function to a CredentialProvider.
+type CredentialProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials implements CredentialProvider.
+func (f CredentialProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// providerAuth authenticates with the credentials of a provider,
+// fetched for every request, with the token when one is provided and
+// the username and password otherwise.
+type providerAuth struct {
+	p CredentialProvider
+}
+
+func (providerAuth) mode() AuthMode { return AuthProvider }
+
+func (a providerAuth) authenticate(ctx context.Context, c *Client, v url.Values) error {
+	cr, err := a.p.Credentials(ctx)
+	if err != nil {
+		return err
+	}
+	if cr.Token != "" {
+		if err := checkCredentials(map[string]string{
+			"accountID":   cr.AccountID,
+			"developerID": cr.DevID,
+		}, "accountID", "developerID"); err != nil {
+			return err
+		}
+		v.Add("t2maccount", cr.AccountID)
+		v.Add("t2mtoken", cr.Token)
+		v.Add("t2mdevid", cr.DevID)
+		return nil
+	}
+	if err := checkCredentials(map[string]string{
+		"accountID":   cr.AccountID,
+		"username":    cr.Username,
+		"password":    cr.Password,
+		"developerID": cr.DevID,
+	}, "accountID", "username", "password", "developerID"); err != nil {
+		return err
+	}
+	v.Add("t2maccount", cr.AccountID)
+	v.Add("t2musername", cr.Username)
+	v.Add("t2mpassword", cr.Password)
+	v.Add("t2mdevid", cr.DevID)
+	return nil
+}
+
+// authStrategy returns the strategy the client was constructed with.
+// Clients that weren't constructed by New or NewWithToken use the token
+// when one is set and the username and password otherwise.
+func (c *Client) authStrategy() authStrategy {
+	if c.auth != nil {
+		return c.auth
+	}
+	if c.Token != "" {
+		return tokenAuth{}
+	}
+	return passwordAuth{}
+}
@@ -0,0 +1,125 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWithToken(t *testing.T) {
+	c, err := NewWithToken(NewTestClient(func(req *http.Request) *http.Response {
+		q := req.URL.Query()
+		assert.Equal(t, "aid", q.Get("t2maccount"))
+		assert.Equal(t, "token", q.Get("t2mtoken"))
+		assert.Equal(t, "devid", q.Get("t2mdevid"))
+		_, ok := q["t2mpassword"]
+		assert.False(t, ok)
+		_, ok = q["t2musername"]
+		assert.False(t, ok)
+		return jsonResponse(200, `{"historyCount":0,"ewonsCount":0,"ewons":[]}`)
+	}), "aid", "token", "devid")
+	assert.NoError(t, err)
+	_, err = c.GetStatus()
+	assert.NoError(t, err)
+
+	_, err = NewWithToken(nil, "aid", "", "devid")
+	assert.True(t, errors.Is(err, ErrMissingCredentials))
+	assert.EqualError(t, err, "missing one or more credentials: token is empty")
+}
+
+func TestPasswordAuth(t *testing.T) {
+	c, err := New(NewTestClient(func(req *http.Request) *http.Response {
+		q := req.URL.Query()
+		assert.Equal(t, "username", q.Get("t2musername"))
+		assert.Equal(t, "password", q.Get("t2mpassword"))
+		_, ok := q["t2mtoken"]
+		assert.False(t, ok)
+		return jsonResponse(200, `{"historyCount":0,"ewonsCount":0,"ewons":[]}`)
+	}), "aid", "username", "password", "devid")
+	assert.NoError(t, err)
+	_, err = c.GetStatus()
+	assert.NoError(t, err)
+}
+
+type ctxKey struct{}
+
+func TestCredentialProvider(t *testing.T) {
+	calls := 0
+	p := CredentialProviderFunc(func(ctx context.Context) (Credentials, error) {
+		calls++
+		assert.Equal(t, "value", ctx.Value(ctxKey{}))
+		if calls == 1 {
+			return Credentials{AccountID: "aid", Username: "username", Password: "password", DevID: "devid"}, nil
+		}
+		return Credentials{AccountID: "aid", Token: "token", DevID: "devid"}, nil
+	})
+	var queries []url.Values
+	c, err := NewWithCredentialProvider(NewTestClient(func(req *http.Request) *http.Response {
+		queries = append(queries, req.URL.Query())
+		return jsonResponse(200, `{"historyCount":0,"ewonsCount":0,"ewons":[]}`)
+	}), p, WithStrictValidation())
+	assert.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	_, err = c.GetStatusContext(ctx)
+	assert.NoError(t, err)
+	_, err = c.GetStatusContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	if assert.Len(t, queries, 2) {
+		assert.Equal(t, "password", queries[0].Get("t2mpassword"))
+		_, ok := queries[0]["t2mtoken"]
+		assert.False(t, ok)
+		assert.Equal(t, "token", queries[1].Get("t2mtoken"))
+		_, ok = queries[1]["t2mpassword"]
+		assert.False(t, ok)
+	}
+	assert.Empty(t, c.Password)
+}
+
+func TestCredentialProviderError(t *testing.T) {
+	sent := false
+	errVault := errors.New("vault sealed")
+	c, err := NewWithCredentialProvider(NewTestClient(func(req *http.Request) *http.Response {
+		sent = true
+		return jsonResponse(200, `{}`)
+	}), CredentialProviderFunc(func(ctx context.Context) (Credentials, error) {
+		return Credentials{}, errVault
+	}))
+	assert.NoError(t, err)
+	_, err = c.GetStatus()
+	assert.True(t, errors.Is(err, errVault))
+	assert.False(t, sent)
+
+	c, err = NewWithCredentialProvider(nil, CredentialProviderFunc(func(ctx context.Context) (Credentials, error) {
+		return Credentials{AccountID: "aid", DevID: "devid"}, nil
+	}))
+	assert.NoError(t, err)
+	_, err = c.GetStatus()
+	assert.True(t, errors.Is(err, ErrMissingCredentials))
+
+	_, err = NewWithCredentialProvider(nil, nil)
+	assert.Error(t, err)
+}
+
+func TestAuthMode(t *testing.T) {
+	c, err := New(nil, "aid", "username", "password", "devid")
+	assert.NoError(t, err)
+	assert.Equal(t, AuthPassword, c.AuthMode())
+
+	c, err = NewWithToken(nil, "aid", "token", "devid")
+	assert.NoError(t, err)
+	assert.Equal(t, AuthToken, c.AuthMode())
+
+	c, err = NewWithCredentialProvider(nil, CredentialProviderFunc(func(ctx context.Context) (Credentials, error) {
+		return Credentials{}, nil
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, AuthProvider, c.AuthMode())
+
+	assert.Equal(t, AuthToken, (&Client{Token: "token"}).AuthMode())
+}
@@ -0,0 +1,170 @@
+package dmweb
+
+import (
+	"context"
+	"time"
+)
+
+// tailInterval is how long BackfillThenTail waits before polling
+// syncdata again once the DataMailbox reported no more data.
+var tailInterval = time.Minute
+
+type tagKey struct {
+	ewonID int
+	tagID  int
+}
+
+// BackfillThenTail delivers all history from from up to now through
+// getdata, then keeps delivering new data as it arrives through
+// syncdata, until ctx is cancelled or fn returns an error.
+//
+// getdata selects points by timestamp while syncdata selects them by
+// upload, so the handoff is managed as follows:
+//  1. syncdata is drained to obtain a transaction covering everything
+//     already in the DataMailbox. This data is not delivered.
+//  2. getdata is paged from from to now.
+//  3. syncdata is tailed from the transaction of step 1.
+//
+// Points uploaded between step 1 and 2 are returned by both getdata and
+// syncdata. Since an eWON uploads the history of a tag in chronological
+// order, these are exactly the backfilled points newer than the newest
+// point of that tag seen in step 1; they are remembered and skipped when
+// syncdata returns them again. Tailed points older than from are dropped.
+//
+// Step 1 downloads the complete DataMailbox once, page by page and
+// without a limit on the number of pages. Both syncdata steps go through
+// a Syncer.
+func (c *Client) BackfillThenTail(ctx context.Context, from time.Time, fn func([]Record) error) error {
+	s := NewSyncer(c, &MemoryCursorStore{})
+
+	// Step 1: establish a transaction at the current end of the mailbox.
+	drained := make(map[tagKey]time.Time)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r, err := s.Next(ctx, func(r *SyncResponse) error {
+			for _, rec := range r.Records() {
+				k := tagKey{rec.EwonID, rec.TagID}
+				if rec.Date.After(drained[k]) {
+					drained[k] = rec.Date
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if !r.MoreDataAvailable {
+			break
+		}
+	}
+
+	// Step 2: backfill by timestamp.
+	overlap := make(map[recordKey]bool)
+	err := c.export(ctx, 0, from, c.now(), func(rs []Record) error {
+		for _, r := range rs {
+			if r.Date.After(drained[tagKey{r.EwonID, r.TagID}]) {
+				overlap[r.key()] = true
+			}
+		}
+		return fn(rs)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Step 3: tail by upload.
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r, err := s.Next(ctx, func(r *SyncResponse) error {
+			var page []Record
+			for _, rec := range r.Records() {
+				if rec.Date.Before(from) {
+					continue
+				}
+				if overlap[rec.key()] {
+					delete(overlap, rec.key())
+					continue
+				}
+				page = append(page, rec)
+			}
+			if len(page) == 0 {
+				return nil
+			}
+			return fn(page)
+		})
+		if err != nil {
+			return err
+		}
+		if r.MoreDataAvailable {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(tailInterval):
+		}
+	}
+}
+
+// BackfillStore persists the pages of a backfill, see Backfill, along
+// with the ID of the transaction of the last stored page.
+type BackfillStore interface {
+	CursorStore
+	// StorePage persists the data of a page. It is called before the
+	// transaction ID of the page is saved.
+	StorePage(ctx context.Context, s *SyncResponse) error
+}
+
+// BackfillProgress reports the progress of a backfill after every page.
+// Pages and Points count the pages and history points stored since the
+// backfill was started or resumed.
+type BackfillProgress struct {
+	Pages             int
+	Points            int
+	TransactionID     string
+	MoreDataAvailable bool
+}
+
+// Backfill downloads the complete history in the DataMailbox through
+// syncdata, passing every page to store.StorePage and then saving its
+// transaction ID with store.Save, like Syncer. When the transaction ID
+// loaded from store isn't empty, the backfill resumes after it, so an
+// interrupted backfill can be restarted with the same store. It returns
+// once the DataMailbox reports no more data available, or with the
+// first error, including the cancellation of ctx; a page whose storing
+// failed is synced again on resume.
+func (c *Client) Backfill(ctx context.Context, store BackfillStore) error {
+	return c.BackfillWithProgress(ctx, store, nil)
+}
+
+// BackfillWithProgress is like Backfill, calling progress, when not nil,
+// after every stored page.
+func (c *Client) BackfillWithProgress(ctx context.Context, store BackfillStore, progress func(BackfillProgress)) error {
+	s := NewSyncer(c, store)
+	var p BackfillProgress
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r, err := s.Next(ctx, func(r *SyncResponse) error {
+			return store.StorePage(ctx, r)
+		})
+		if err != nil {
+			return err
+		}
+		p.Pages++
+		p.Points += r.HistoryPointCount()
+		p.TransactionID = r.TransactionID
+		p.MoreDataAvailable = r.MoreDataAvailable
+		if progress != nil {
+			progress(p)
+		}
+		if !r.MoreDataAvailable {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackfillThenTail(t *testing.T) {
+	syncCalls := 0
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		q := req.URL.Query()
+		switch req.URL.Path {
+		case "/syncdata":
+			syncCalls++
+			switch q.Get("lastTransactionId") {
+			case "":
+				// Everything in the mailbox before the backfill.
+				return jsonResponse(200, `{"success":true,"transactionId":"1","moreDataAvailable":false,"ewons":[{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"TAG","history":[
+					{"date":"2018-11-08T09:00:00Z","value":1},
+					{"date":"2018-11-08T10:00:00Z","value":2}]}]}]}`)
+			case "1":
+				// 10:05 was uploaded between the drain and the backfill.
+				return jsonResponse(200, `{"success":true,"transactionId":"2","moreDataAvailable":false,"ewons":[{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"TAG","history":[
+					{"date":"2018-11-08T10:05:00Z","value":3},
+					{"date":"2018-11-08T10:10:00Z","value":4}]}]}]}`)
+			}
+		case "/getdata":
+			assert.Equal(t, "2018-11-08T09:30:00Z", q.Get("from"))
+			return jsonResponse(200, `{"success":true,"moreDataAvailable":false,"ewons":[{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"TAG","history":[
+				{"date":"2018-11-08T10:00:00Z","value":2},
+				{"date":"2018-11-08T10:05:00Z","value":3}]}]}]}`)
+		}
+		t.Errorf("unexpected request %s", req.URL)
+		return jsonResponse(404, `{"success":false,"code":404,"message":"not found"}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	from, _ := time.Parse(time.RFC3339, "2018-11-08T09:30:00Z")
+	var got [][]float64
+	err := c.BackfillThenTail(ctx, from, func(rs []Record) error {
+		var vs []float64
+		for _, r := range rs {
+			vs = append(vs, r.Value)
+		}
+		got = append(got, vs)
+		if syncCalls == 2 {
+			cancel()
+		}
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, [][]float64{{2, 3}, {4}}, got)
+}
+
+func TestBackfillThenTailDrainsAllPages(t *testing.T) {
+	pages := DefaultMaxSyncPages + 1
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/getdata" {
+			return jsonResponse(200, `{"success":true,"moreDataAvailable":false,"ewons":[]}`)
+		}
+		n, _ := strconv.Atoi(req.URL.Query().Get("lastTransactionId"))
+		if n < pages {
+			return jsonResponse(200, fmt.Sprintf(`{"success":true,"transactionId":"%d","moreDataAvailable":true,"ewons":[]}`, n+1))
+		}
+		// Tailing starts after the last drained page.
+		assert.Equal(t, pages, n)
+		return jsonResponse(200, `{"success":true,"transactionId":"tail","moreDataAvailable":false,"ewons":[{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"TAG","history":[
+			{"date":"2018-11-08T10:10:00Z","value":4}]}]}]}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	from, _ := time.Parse(time.RFC3339, "2018-11-08T09:30:00Z")
+	var got []Record
+	err := c.BackfillThenTail(ctx, from, func(rs []Record) error {
+		got = append(got, rs...)
+		cancel()
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, 4.0, got[0].Value)
+	}
+}
+
+type testBackfillStore struct {
+	MemoryCursorStore
+	pages []string
+	fail  string
+}
+
+func (s *testBackfillStore) StorePage(ctx context.Context, r *SyncResponse) error {
+	if r.TransactionID == s.fail {
+		return errors.New("disk full")
+	}
+	s.pages = append(s.pages, r.TransactionID)
+	return nil
+}
+
+func TestBackfill(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		switch req.URL.Query().Get("lastTransactionId") {
+		case "":
+			return jsonResponse(200, `{"success":true,"transactionId":"1","moreDataAvailable":true,"ewons":[{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"TAG","history":[
+				{"date":"2018-11-08T09:00:00Z","value":1},
+				{"date":"2018-11-08T10:00:00Z","value":2}]}]}]}`)
+		case "1":
+			return jsonResponse(200, `{"success":true,"transactionId":"2","moreDataAvailable":true,"ewons":[]}`)
+		case "2":
+			return jsonResponse(200, `{"success":true,"transactionId":"3","moreDataAvailable":false,"ewons":[]}`)
+		}
+		return jsonResponse(500, `{"success":false,"code":500,"message":"unexpected"}`)
+	})
+
+	// Storing page 2 fails, so the backfill stops after page 1.
+	store := &testBackfillStore{fail: "2"}
+	var progress []BackfillProgress
+	err := c.BackfillWithProgress(context.Background(), store, func(p BackfillProgress) {
+		progress = append(progress, p)
+	})
+	assert.EqualError(t, err, "disk full")
+	assert.Equal(t, []string{"1"}, store.pages)
+	assert.Equal(t, []BackfillProgress{{Pages: 1, Points: 2, TransactionID: "1", MoreDataAvailable: true}}, progress)
+
+	// Resuming syncs page 2 again.
+	store.fail = ""
+	assert.NoError(t, c.Backfill(context.Background(), store))
+	assert.Equal(t, []string{"1", "2", "3"}, store.pages)
+	id, _ := store.Load()
+	assert.Equal(t, "3", id)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, c.Backfill(ctx, &testBackfillStore{}))
+}
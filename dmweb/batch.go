@@ -0,0 +1,98 @@
+package dmweb
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultEwonConcurrency is the number of getewon requests
+// GetEwonsWithTags keeps in flight.
+const DefaultEwonConcurrency = 4
+
+// GetEwonsWithTags is like GetEwons, with each eWON fully populated with
+// its tags, which getewons leaves out. It is GetAllEwonConfigs with a
+// concurrency of DefaultEwonConcurrency; eWONs that could not be fetched
+// are left out and reported as EwonErrors.
+func (c *Client) GetEwonsWithTags(ctx context.Context) (Ewons, error) {
+	return c.GetAllEwonConfigs(ctx, DefaultEwonConcurrency)
+}
+
+// GetAllEwonConfigs returns every eWON of the account with its full tag
+// configuration. The eWONs are listed with GetEwons and then fetched
+// one by one with getewon, with at most concurrency requests in flight.
+// eWONs that could not be fetched are left out of the result, in list
+// order otherwise, and reported as EwonErrors.
+func (c *Client) GetAllEwonConfigs(ctx context.Context, concurrency int) ([]*Ewon, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	es, err := c.GetEwonsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(es))
+	for i, e := range es {
+		ids[i] = e.ID
+	}
+	configs, errs := c.GetEwonsByIDs(ctx, ids, concurrency)
+
+	out := make([]*Ewon, 0, len(es))
+	for _, id := range ids {
+		if e, ok := configs[id]; ok {
+			out = append(out, e)
+		}
+	}
+	if len(errs) > 0 {
+		return out, EwonErrors(errs)
+	}
+	return out, nil
+}
+
+// GetEwonsByIDs fetches the eWONs with the given IDs with getewon, with
+// at most concurrency requests in flight so as not to exceed the Talk2M
+// rate limits. It returns the eWONs that were fetched and the errors of
+// those that weren't, both keyed by ID. Every request is bounded by the
+// Timeout of the HTTP client, so a single hung request doesn't stall the
+// batch. Once ctx is done, the remaining IDs fail with ctx.Err().
+func (c *Client) GetEwonsByIDs(ctx context.Context, ids []int, concurrency int) (map[int]*Ewon, map[int]error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	ewons := make(map[int]*Ewon, len(ids))
+	errs := make(map[int]error)
+	sem := make(chan struct{}, concurrency)
+	for _, id := range ids {
+		if ctx.Err() == nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			mu.Lock()
+			errs[id] = err
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			e, err := c.getEwonByIdentifier(ctx, "id", id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[id] = err
+				return
+			}
+			ewons[id] = e
+		}(id)
+	}
+	wg.Wait()
+	return ewons, errs
+}
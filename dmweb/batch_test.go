@@ -0,0 +1,111 @@
+package dmweb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAllEwonConfigs(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		inFlight int
+		maxIn    int
+	)
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/getewons" {
+			return jsonResponse(200, `{"success":true,"ewons":[{"id":1,"name":"Ewon1"},{"id":2,"name":"Ewon2"},{"id":3,"name":"Ewon3"}]}`)
+		}
+		mu.Lock()
+		inFlight++
+		if inFlight > maxIn {
+			maxIn = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		id := req.URL.Query().Get("id")
+		if id == "2" {
+			return jsonResponse(404, `{"success":false,"code":404,"message":"No eWON found for id '2'"}`)
+		}
+		return jsonResponse(200, fmt.Sprintf(`{"success":true,"id":%s,"name":"Ewon%s","tags":[{"id":10,"name":"TAG"}]}`, id, id))
+	})
+
+	es, err := c.GetAllEwonConfigs(context.Background(), 2)
+	if assert.Len(t, es, 2) {
+		assert.Equal(t, 1, es[0].ID)
+		assert.Equal(t, 3, es[1].ID)
+		assert.Equal(t, "TAG", es[1].Tags[0].Name)
+	}
+	if assert.IsType(t, EwonErrors{}, err) {
+		errs := err.(EwonErrors)
+		assert.Len(t, errs, 1)
+		assert.EqualError(t, errs[2], "No eWON found for id '2'")
+	}
+	assert.Equal(t, 2, maxIn)
+}
+
+func TestGetEwonsByIDs(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		inFlight int
+		maxIn    int
+	)
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxIn {
+			maxIn = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		id := req.URL.Query().Get("id")
+		if id == "4" {
+			return jsonResponse(404, `{"success":false,"code":404,"message":"No eWON found for id '4'"}`)
+		}
+		return jsonResponse(200, fmt.Sprintf(`{"success":true,"id":%s,"name":"Ewon%s"}`, id, id))
+	})
+
+	ewons, errs := c.GetEwonsByIDs(context.Background(), []int{1, 2, 3, 4, 5}, 2)
+	assert.Len(t, ewons, 4)
+	assert.Equal(t, "Ewon5", ewons[5].Name)
+	if assert.Len(t, errs, 1) {
+		assert.EqualError(t, errs[4], "No eWON found for id '4'")
+	}
+	assert.Equal(t, 2, maxIn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ewons, errs = c.GetEwonsByIDs(ctx, []int{1, 2}, 1)
+	assert.Empty(t, ewons)
+	assert.Len(t, errs, 2)
+}
+
+func TestGetEwonsWithTags(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/getewons" {
+			return jsonResponse(200, `{"success":true,"ewons":[{"id":1,"name":"Ewon1"},{"id":2,"name":"Ewon2"}]}`)
+		}
+		id := req.URL.Query().Get("id")
+		return jsonResponse(200, fmt.Sprintf(`{"success":true,"id":%s,"name":"Ewon%s","tags":[{"id":10,"name":"TAG%s"}]}`, id, id, id))
+	})
+
+	es, err := c.GetEwonsWithTags(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, es, 2) {
+		assert.Equal(t, "TAG1", es[0].Tags[0].Name)
+		assert.Equal(t, "TAG2", es[1].Tags[0].Name)
+	}
+}
@@ -0,0 +1,132 @@
+package dmweb
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds the number of responses cached by WithCache.
+const maxCacheEntries = 1024
+
+// cachedEndpoints are the endpoints whose responses WithCache caches.
+// They return metadata that changes slowly, unlike getdata and syncdata.
+var cachedEndpoints = map[string]bool{
+	EndpointGetStatus: true,
+	EndpointGetEwons:  true,
+	EndpointGetEwon:   true,
+}
+
+// cacheEntry is a cached successful response.
+type cacheEntry struct {
+	status     string
+	statusCode int
+	header     http.Header
+	body       []byte
+	expires    time.Time
+}
+
+// response returns a new response with the cached status, header and
+// body.
+func (e *cacheEntry) response() *http.Response {
+	return &http.Response{
+		Status:     e.status,
+		StatusCode: e.statusCode,
+		Header:     e.header.Clone(),
+		Body:       ioutil.NopCloser(bytes.NewReader(e.body)),
+	}
+}
+
+// responseCache caches responses for a fixed time, keyed by endpoint and
+// parameters. It is safe for concurrent use.
+type responseCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{ttl: ttl, entries: make(map[string]*cacheEntry)}
+}
+
+func (rc *responseCache) get(key string, now time.Time) (*cacheEntry, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	e, ok := rc.entries[key]
+	if !ok || !now.Before(e.expires) {
+		return nil, false
+	}
+	return e, true
+}
+
+// put caches e, evicting the expired entries, and then the entry that
+// expires first, when the cache is full.
+func (rc *responseCache) put(key string, e *cacheEntry, now time.Time) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if _, ok := rc.entries[key]; !ok && len(rc.entries) >= maxCacheEntries {
+		var oldest string
+		for k, o := range rc.entries {
+			if !now.Before(o.expires) {
+				delete(rc.entries, k)
+			} else if oldest == "" || o.expires.Before(rc.entries[oldest].expires) {
+				oldest = k
+			}
+		}
+		if len(rc.entries) >= maxCacheEntries {
+			delete(rc.entries, oldest)
+		}
+	}
+	rc.entries[key] = e
+}
+
+func (rc *responseCache) clear() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries = make(map[string]*cacheEntry)
+}
+
+// cachedRequest serves a request to a cached endpoint from the cache,
+// or sends it and caches the response.
+func (c *Client) cachedRequest(ctx context.Context, endpoint string, params url.Values, header http.Header) (*http.Response, error) {
+	key := endpoint + "?" + params.Encode()
+	if e, ok := c.cache.get(key, c.now()); ok {
+		return e.response(), nil
+	}
+	res, err := c.send(ctx, endpoint, params, header)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotModified {
+		// Only valid for the conditional request that got it.
+		return res, nil
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	now := c.now()
+	e := &cacheEntry{
+		status:     res.Status,
+		statusCode: res.StatusCode,
+		header:     res.Header,
+		body:       body,
+		expires:    now.Add(c.cache.ttl),
+	}
+	c.cache.put(key, e, now)
+	return e.response(), nil
+}
+
+// InvalidateCache empties the cache of the client, see WithCache, so
+// that the next requests are sent to the API.
+func (c *Client) InvalidateCache() {
+	if c.cache != nil {
+		c.cache.clear()
+	}
+}
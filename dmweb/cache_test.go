@@ -0,0 +1,94 @@
+package dmweb
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCache(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	c, err := New(NewTestClient(func(req *http.Request) *http.Response {
+		mu.Lock()
+		requests[req.URL.Path]++
+		mu.Unlock()
+		switch req.URL.Path {
+		case "/getstatus":
+			return jsonResponse(200, `{"historyCount":3,"ewonsCount":0,"ewons":[]}`)
+		case "/getewon":
+			return jsonResponse(200, fmt.Sprintf(`{"id":%s,"name":"Ewon"}`, req.URL.Query().Get("id")))
+		case "/getewons":
+			return jsonResponse(500, `{"success":false,"code":500,"message":"error"}`)
+		}
+		return jsonResponse(200, `{"success":true,"transactionId":"1","ewons":[]}`)
+	}), "aid", "username", "password", "devid", WithCache(time.Minute))
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s, err := c.GetStatus()
+			assert.NoError(t, err)
+			assert.Equal(t, 3, s.HistoryCount)
+		}()
+	}
+	wg.Wait()
+	s, err := c.GetStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, s.HistoryCount)
+	assert.Equal(t, 200, s.Meta.StatusCode)
+	assert.True(t, requests["/getstatus"] < 11)
+	n := requests["/getstatus"]
+
+	for _, id := range []int{1, 2, 1} {
+		e, err := c.GetEwonByID(id)
+		assert.NoError(t, err)
+		assert.Equal(t, id, e.ID)
+	}
+	assert.Equal(t, 2, requests["/getewon"])
+
+	for i := 0; i < 2; i++ {
+		_, err = c.GetEwons()
+		assert.Error(t, err)
+		_, err = c.FirstSyncData()
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, requests["/getewons"])
+	assert.Equal(t, 2, requests["/syncdata"])
+
+	c.InvalidateCache()
+	_, err = c.GetStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, n+1, requests["/getstatus"])
+
+	_, err = New(nil, "aid", "username", "password", "devid", WithCache(0))
+	assert.Error(t, err)
+}
+
+func TestResponseCacheExpiry(t *testing.T) {
+	rc := newResponseCache(time.Minute)
+	now := time.Now()
+	rc.put("a", &cacheEntry{expires: now.Add(time.Minute)}, now)
+	_, ok := rc.get("a", now)
+	assert.True(t, ok)
+	_, ok = rc.get("a", now.Add(time.Minute))
+	assert.False(t, ok)
+
+	for i := 0; i < maxCacheEntries+10; i++ {
+		rc.put(fmt.Sprint(i), &cacheEntry{expires: now.Add(time.Duration(i) * time.Second)}, now)
+	}
+	assert.Len(t, rc.entries, maxCacheEntries)
+	_, ok = rc.get("1", now)
+	assert.False(t, ok)
+	_, ok = rc.get("a", now)
+	assert.True(t, ok)
+	_, ok = rc.get(fmt.Sprint(maxCacheEntries+9), now)
+	assert.True(t, ok)
+}
@@ -0,0 +1,16 @@
+package dmweb
+
+import "time"
+
+// now returns the current time of the client's clock, see WithClock.
+func (c *Client) now() time.Time {
+	if c.clock != nil {
+		return c.clock()
+	}
+	return time.Now()
+}
+
+// since returns the time elapsed since t on the client's clock.
+func (c *Client) since(t time.Time) time.Duration {
+	return c.now().Sub(t)
+}
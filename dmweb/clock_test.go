@@ -0,0 +1,35 @@
+package dmweb
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithClock(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	requests := 0
+	c, err := New(NewTestClient(func(req *http.Request) *http.Response {
+		requests++
+		return jsonResponse(200, `{"historyCount":0,"ewonsCount":0,"ewons":[]}`)
+	}), "aid", "username", "password", "devid",
+		WithClock(func() time.Time { return now }), WithCache(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, now, c.now())
+
+	_, err = c.GetStatus()
+	assert.NoError(t, err)
+	now = now.Add(59 * time.Second)
+	_, err = c.GetStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+	now = now.Add(time.Second)
+	_, err = c.GetStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests)
+
+	_, err = New(nil, "aid", "username", "password", "devid", WithClock(nil))
+	assert.Error(t, err)
+}
@@ -0,0 +1,31 @@
+package dmweb
+
+import "sync/atomic"
+
+// Close releases the resources of the client: it drops the cached
+// responses and closes the idle connections of the HTTP transport, when
+// the client owns it, i.e. New made a copy of it for options like
+// WithProxy or WithMaxIdleConns. Shared transports, http.DefaultTransport
+// included, are left alone. The client is
+// unusable afterward; its requests, including the next pages of streams
+// like SyncDataStream, fail with ErrClientClosed. Requests in flight are
+// not interrupted, cancel their context for that. Calling Close again
+// has no effect.
+func (c *Client) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		return nil
+	}
+	c.InvalidateCache()
+	c.statusMu.Lock()
+	c.lastStatus = nil
+	c.statusMu.Unlock()
+	if c.ownsTransport && c.Client != nil {
+		c.Client.CloseIdleConnections()
+	}
+	return nil
+}
+
+// isClosed reports whether Close was called.
+func (c *Client) isClosed() bool {
+	return atomic.LoadInt32(&c.closed) != 0
+}
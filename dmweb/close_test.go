@@ -0,0 +1,46 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClose(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true,"transactionId":"1","moreDataAvailable":true,"ewons":[]}`))
+	}))
+	defer srv.Close()
+
+	c, err := New(nil, "aid", "username", "password", "devid",
+		WithBaseURL(srv.URL+"/"), WithMaxIdleConns(1))
+	assert.NoError(t, err)
+	assert.True(t, c.ownsTransport)
+
+	pages, err := c.SyncDataStream(context.Background(), "")
+	assert.NoError(t, err)
+	p := <-pages
+	assert.NoError(t, p.Err)
+
+	assert.NoError(t, c.Close())
+	assert.NoError(t, c.Close())
+	var got []SyncPage
+	for p := range pages {
+		got = append(got, p)
+	}
+	if assert.NotEmpty(t, got) {
+		assert.True(t, errors.Is(got[len(got)-1].Err, ErrClientClosed))
+	}
+	_, err = c.GetStatus()
+	assert.Equal(t, ErrClientClosed, err)
+
+	shared, err := New(http.DefaultClient, "aid", "username", "password", "devid")
+	assert.NoError(t, err)
+	assert.False(t, shared.ownsTransport)
+	assert.NoError(t, shared.Close())
+}
@@ -0,0 +1,30 @@
+package dmweb
+
+import "time"
+
+// Completeness returns the fraction, between 0 and 1, of the points
+// expected between from and to, both inclusive, that are present in the
+// history of tag, based on the logging interval registered for it with
+// WithLoggingInterval. The boolean is false when no logging interval is
+// registered for the tag.
+func (c *Client) Completeness(tag DataTag, from, to time.Time) (float64, bool) {
+	interval, ok := c.loggingIntervals[tag.Name]
+	if !ok {
+		return 0, false
+	}
+	// A fully logged window has a point on from and on to.
+	expected := int(to.Sub(from)/interval) + 1
+	if expected <= 0 {
+		return 1, true
+	}
+	n := 0
+	for _, h := range tag.History {
+		if !h.Date.Before(from) && !h.Date.After(to) {
+			n++
+		}
+	}
+	if n >= expected {
+		return 1, true
+	}
+	return float64(n) / float64(expected), true
+}
@@ -0,0 +1,45 @@
+package dmweb
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompleteness(t *testing.T) {
+	c, err := New(&http.Client{}, "aid", "username", "password", "devid", WithLoggingInterval("TAG_2", 2*time.Second))
+	assert.NoError(t, err)
+
+	var tag DataTag
+	err = json.Unmarshal([]byte(`{"id":1,"name":"TAG_2","history":[
+		{"date":"2018-11-08T14:17:58Z","value":0},
+		{"date":"2018-11-08T14:18:00Z","value":0},
+		{"date":"2018-11-08T14:18:02Z","value":0},
+		{"date":"2018-11-08T14:18:06Z","value":0}
+	]}`), &tag)
+	assert.NoError(t, err)
+
+	from, _ := time.Parse(time.RFC3339, "2018-11-08T14:17:58Z")
+	got, ok := c.Completeness(tag, from, from.Add(10*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, 4.0/6, got)
+
+	// A sample exactly on to counts, and a full window is complete.
+	err = json.Unmarshal([]byte(`{"id":1,"name":"TAG_2","history":[
+		{"date":"2018-11-08T14:17:58Z","value":0},
+		{"date":"2018-11-08T14:18:00Z","value":0},
+		{"date":"2018-11-08T14:18:02Z","value":0}
+	]}`), &tag)
+	assert.NoError(t, err)
+	got, _ = c.Completeness(tag, from, from.Add(4*time.Second))
+	assert.Equal(t, 1.0, got)
+	got, _ = c.Completeness(tag, from, from.Add(6*time.Second))
+	assert.Equal(t, 0.75, got)
+
+	tag.Name = "Unknown"
+	_, ok = c.Completeness(tag, from, from.Add(10*time.Second))
+	assert.False(t, ok)
+}
@@ -0,0 +1,73 @@
+package dmweb
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// ErrCSVNotSupported is returned by GetDataCSV when the server ignores
+// the request for CSV and responds in another format, usually JSON.
+var ErrCSVNotSupported = errors.New("server did not respond with CSV")
+
+// GetDataCSV is like GetDataWithParams, but asks the server for CSV
+// rather than JSON and returns the body of the response as is, without
+// decoding it, which keeps memory use flat for large history dumps.
+// The caller must close the returned body.
+// CSV is requested with an Accept: text/csv header only: DMWeb documents
+// no format parameter for getdata, so this depends on the server
+// honouring that header. When the server responds in another format,
+// GetDataCSV returns an error wrapping ErrCSVNotSupported.
+// As the body isn't decoded, the filter of WithEwonFilter can't drop
+// eWONs from it: with a filter, params must select an allowed eWON with
+// EwonID.
+func (c *Client) GetDataCSV(params GetDataParams) (io.ReadCloser, error) {
+	return c.GetDataCSVContext(context.Background(), params)
+}
+
+// GetDataCSVContext is like GetDataCSV, with ctx controlling the request.
+func (c *Client) GetDataCSVContext(ctx context.Context, params GetDataParams) (io.ReadCloser, error) {
+	params, qs, err := c.getDataQuery(params)
+	if err != nil {
+		return nil, err
+	}
+	if c.ewonFilter != nil {
+		if params.EwonID == nil {
+			return nil, errors.New("the eWON filter can't be applied to CSV, set EwonID")
+		}
+		if !c.ewonFilter.allows(*params.EwonID) {
+			return nil, fmt.Errorf("ewon %d is excluded by the eWON filter", *params.EwonID)
+		}
+	}
+	res, err := c.request(ctx, EndpointGetData, qs, http.Header{"Accept": {"text/csv"}})
+	if err != nil {
+		return nil, err
+	}
+	ct := res.Header.Get("Content-Type")
+	mt, _, _ := mime.ParseMediaType(ct)
+	if mt != "text/csv" && mt != "application/csv" {
+		res.Body.Close()
+		return nil, fmt.Errorf("%w: got content type %q", ErrCSVNotSupported, ct)
+	}
+	return res.Body, nil
+}
+
+// GetDataCSVRecords is like GetDataCSV, with the CSV parsed into records.
+func (c *Client) GetDataCSVRecords(params GetDataParams) ([][]string, error) {
+	return c.GetDataCSVRecordsContext(context.Background(), params)
+}
+
+// GetDataCSVRecordsContext is like GetDataCSVRecords, with ctx
+// controlling the request.
+func (c *Client) GetDataCSVRecordsContext(ctx context.Context, params GetDataParams) ([][]string, error) {
+	body, err := c.GetDataCSVContext(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return csv.NewReader(body).ReadAll()
+}
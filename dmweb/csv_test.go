@@ -0,0 +1,92 @@
+package dmweb
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDataCSV(t *testing.T) {
+	const body = "ewonId,tagId,date,value\n1,2,2020-01-01T00:00:00Z,3.5\n"
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "/getdata", req.URL.Path)
+		assert.Equal(t, "text/csv", req.Header.Get("Accept"))
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+			Header:     http.Header{"Content-Type": {"text/csv; charset=UTF-8"}},
+		}
+	})
+
+	rc, err := c.GetDataCSV(GetDataParams{})
+	if assert.NoError(t, err) {
+		b, err := ioutil.ReadAll(rc)
+		assert.NoError(t, err)
+		assert.Equal(t, body, string(b))
+		assert.NoError(t, rc.Close())
+	}
+
+	records, err := c.GetDataCSVRecords(GetDataParams{})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"ewonId", "tagId", "date", "value"},
+		{"1", "2", "2020-01-01T00:00:00Z", "3.5"},
+	}, records)
+}
+
+func TestGetDataCSVNotSupported(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(200, `{"success":true,"ewons":[]}`)
+	})
+
+	_, err := c.GetDataCSV(GetDataParams{})
+	assert.True(t, errors.Is(err, ErrCSVNotSupported))
+	_, err = c.GetDataCSVRecords(GetDataParams{})
+	assert.True(t, errors.Is(err, ErrCSVNotSupported))
+}
+
+func TestGetDataCSVParams(t *testing.T) {
+	var q url.Values
+	now := time.Date(2018, 11, 8, 13, 17, 58, 0, time.UTC)
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		q = req.URL.Query()
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("ewonId\n1\n")),
+			Header:     http.Header{"Content-Type": {"text/csv"}},
+		}
+	})
+	assert.NoError(t, WithClock(func() time.Time { return now })(c))
+
+	// ConfigOnly is resolved like for JSON.
+	rc, err := c.GetDataCSV(GetDataParams{ConfigOnly: true})
+	if assert.NoError(t, err) {
+		rc.Close()
+	}
+	assert.Equal(t, "2018-11-08T13:17:58Z", q.Get("from"))
+	assert.Equal(t, "2018-11-08T13:17:58Z", q.Get("to"))
+	_, ok := q["fullConfig"]
+	assert.True(t, ok)
+
+	// The eWON filter needs an allowed EwonID.
+	assert.NoError(t, WithEwonFilter([]int{1}, nil)(c))
+	q = nil
+	_, err = c.GetDataCSV(GetDataParams{})
+	assert.Error(t, err)
+	ewonID := 2
+	_, err = c.GetDataCSV(GetDataParams{EwonID: &ewonID})
+	assert.Error(t, err)
+	assert.Nil(t, q)
+	ewonID = 1
+	rc, err = c.GetDataCSV(GetDataParams{EwonID: &ewonID})
+	if assert.NoError(t, err) {
+		rc.Close()
+	}
+	assert.Equal(t, "1", q.Get("ewonId"))
+}
@@ -0,0 +1,99 @@
+package dmweb
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvFileName returns the name of the CSV file of a tag, with the
+// characters other than letters, digits, dots, dashes and underscores of
+// the eWON and tag names replaced by underscores.
+func csvFileName(ewonName, tagName string) string {
+	sanitize := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+				return r
+			}
+			return '_'
+		}, s)
+	}
+	return sanitize(ewonName) + "_" + sanitize(tagName) + ".csv"
+}
+
+// writeHistoryCSV writes points to w as CSV with a date,value,quality
+// header.
+func writeHistoryCSV(w io.Writer, points []HistoryPoint) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"date", "value", "quality"}); err != nil {
+		return err
+	}
+	for _, p := range points {
+		err := cw.Write([]string{
+			p.Date.UTC().Format(time.RFC3339Nano),
+			strconv.FormatFloat(p.Value, 'g', -1, 64),
+			string(p.Quality),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteCSV writes the history of every tag in the response to a CSV file
+// in dir named <ewon>_<tag>.csv, with the columns date, value and
+// quality. Characters of the names that aren't safe in file names, like
+// slashes and spaces, are replaced by underscores. Existing files are
+// overwritten; tags whose names map to the same file are an error.
+func (s *SyncResponse) WriteCSV(dir string) error {
+	names := make(map[string]bool)
+	for _, e := range s.Ewons {
+		for _, t := range e.Tags {
+			name := csvFileName(e.Name, t.Name)
+			if names[name] {
+				return fmt.Errorf("eWON %q tag %q: duplicate file name %s", e.Name, t.Name, name)
+			}
+			names[name] = true
+			if err := writeCSVFile(filepath.Join(dir, name), t.History); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeCSVFile(path string, points []HistoryPoint) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeHistoryCSV(f, points); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteCSVTo writes the history of the tag tagID of the eWON ewonID in
+// the response to w as CSV, like WriteCSV.
+func (s *SyncResponse) WriteCSVTo(w io.Writer, ewonID, tagID int) error {
+	for _, e := range s.Ewons {
+		if e.ID != ewonID {
+			continue
+		}
+		for _, t := range e.Tags {
+			if t.ID == tagID {
+				return writeHistoryCSV(w, t.History)
+			}
+		}
+	}
+	return fmt.Errorf("no tag %d of eWON %d in the response", tagID, ewonID)
+}
@@ -0,0 +1,39 @@
+package dmweb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCSV(t *testing.T) {
+	d := time.Date(2018, 11, 8, 14, 17, 58, 0, time.UTC)
+	s := &SyncResponse{Ewons: []DataEwon{{ID: 1, Name: "Line 1", Tags: []DataTag{
+		{ID: 10, Name: "temp/in", History: []HistoryPoint{
+			{Date: d, Value: 1.5, Quality: QualityGood},
+			{Date: d.Add(time.Second), Value: 2},
+		}},
+		{ID: 11, Name: "Pressure"},
+	}}}}
+
+	dir := t.TempDir()
+	assert.NoError(t, s.WriteCSV(dir))
+	b, err := ioutil.ReadFile(filepath.Join(dir, "Line_1_temp_in.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, "date,value,quality\n2018-11-08T14:17:58Z,1.5,good\n2018-11-08T14:17:59Z,2,\n", string(b))
+	b, err = ioutil.ReadFile(filepath.Join(dir, "Line_1_Pressure.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, "date,value,quality\n", string(b))
+
+	var buf bytes.Buffer
+	assert.NoError(t, s.WriteCSVTo(&buf, 1, 10))
+	assert.Equal(t, "date,value,quality\n2018-11-08T14:17:58Z,1.5,good\n2018-11-08T14:17:59Z,2,\n", buf.String())
+	assert.EqualError(t, s.WriteCSVTo(&buf, 1, 12), "no tag 12 of eWON 1 in the response")
+
+	s.Ewons[0].Tags[1].Name = "temp in"
+	assert.Error(t, s.WriteCSV(dir))
+}
@@ -0,0 +1,87 @@
+package dmweb
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DataType is the data type of a tag.
+type DataType string
+
+// Data types of tags.
+const (
+	DataTypeFloat  DataType = "Float"
+	DataTypeInt    DataType = "Int"
+	DataTypeBool   DataType = "Bool"
+	DataTypeString DataType = "String"
+)
+
+// dataTypes maps the lowercased spellings of the known data types to
+// their constants.
+var dataTypes = map[string]DataType{
+	"float":   DataTypeFloat,
+	"int":     DataTypeInt,
+	"integer": DataTypeInt,
+	"bool":    DataTypeBool,
+	"boolean": DataTypeBool,
+	"string":  DataTypeString,
+}
+
+// UnmarshalJSON decodes a data type, normalizing the spelling of known
+// data types, e.g. "float" to DataTypeFloat. Unknown data types are kept
+// as is, see Known.
+func (d *DataType) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if dt, ok := dataTypes[strings.ToLower(s)]; ok {
+		*d = dt
+		return nil
+	}
+	*d = DataType(s)
+	return nil
+}
+
+// Known reports whether d is one of the DataType constants.
+func (d DataType) Known() bool {
+	switch d {
+	case DataTypeFloat, DataTypeInt, DataTypeBool, DataTypeString:
+		return true
+	}
+	return false
+}
+
+// typedValue converts v to the Go type of d: float64 for DataTypeFloat,
+// int64 for DataTypeInt and bool for DataTypeBool.
+func typedValue(d DataType, v float64) (interface{}, error) {
+	switch d {
+	case DataTypeFloat:
+		return v, nil
+	case DataTypeInt:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return nil, fmt.Errorf("value %v is not an integer", v)
+		}
+		return int64(v), nil
+	case DataTypeBool:
+		return v != 0, nil
+	case DataTypeString:
+		return nil, fmt.Errorf("value of data type %s is not numeric", d)
+	}
+	return nil, fmt.Errorf("unknown data type %q", d)
+}
+
+// TypedValue returns the value of the tag as the Go type of its data
+// type: float64 for Float, int64 for Int and bool for Bool.
+// It returns an error for String and unknown data types.
+func (t *Tag) TypedValue() (interface{}, error) {
+	return typedValue(t.DataType, t.Value)
+}
+
+// TypedValue returns the value of the tag as the Go type of its data
+// type, like Tag.TypedValue.
+func (t *DataTag) TypedValue() (interface{}, error) {
+	return typedValue(t.DataType, t.Value)
+}
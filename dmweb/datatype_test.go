@@ -0,0 +1,60 @@
+package dmweb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataTypeUnmarshalJSON(t *testing.T) {
+	var tags []Tag
+	err := json.Unmarshal([]byte(`[
+		{"dataType":"Float"},
+		{"dataType":"float"},
+		{"dataType":"Integer"},
+		{"dataType":"BOOL"},
+		{"dataType":"String"},
+		{"dataType":"DWord"}
+	]`), &tags)
+	assert.NoError(t, err)
+	var got []DataType
+	for _, tag := range tags {
+		got = append(got, tag.DataType)
+	}
+	assert.Equal(t, []DataType{DataTypeFloat, DataTypeFloat, DataTypeInt, DataTypeBool, DataTypeString, "DWord"}, got)
+	assert.True(t, got[0].Known())
+	assert.False(t, got[5].Known())
+}
+
+func TestTypedValue(t *testing.T) {
+	tables := []struct {
+		dt  DataType
+		v   float64
+		out interface{}
+		err string
+	}{
+		{DataTypeFloat, 1.5, 1.5, ""},
+		{DataTypeInt, 42, int64(42), ""},
+		{DataTypeInt, 1.5, nil, "value 1.5 is not an integer"},
+		{DataTypeBool, 1, true, ""},
+		{DataTypeBool, 0, false, ""},
+		{DataTypeString, 0, nil, "value of data type String is not numeric"},
+		{"DWord", 0, nil, `unknown data type "DWord"`},
+	}
+	for _, table := range tables {
+		tag := Tag{DataType: table.dt, Value: table.v}
+		out, err := tag.TypedValue()
+		if table.err != "" {
+			assert.EqualError(t, err, table.err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, table.out, out)
+	}
+
+	dt := DataTag{DataType: DataTypeInt, Value: 3}
+	out, err := dt.TypedValue()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), out)
+}
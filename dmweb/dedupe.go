@@ -0,0 +1,53 @@
+package dmweb
+
+// dedupe removes, in place, the history points of ewons whose eWON, tag
+// and timestamp are in seen or occur earlier in ewons, recording the
+// kept ones in seen. It returns the number of points removed.
+func dedupe(ewons []DataEwon, seen map[recordKey]bool) int {
+	removed := 0
+	for i := range ewons {
+		e := &ewons[i]
+		for j := range e.Tags {
+			t := &e.Tags[j]
+			kept := t.History[:0]
+			for _, h := range t.History {
+				k := recordKey{ewonID: e.ID, tagID: t.ID, date: h.Date}
+				if seen[k] {
+					removed++
+					continue
+				}
+				seen[k] = true
+				kept = append(kept, h)
+			}
+			t.History = kept
+		}
+	}
+	return removed
+}
+
+// Dedupe removes the history points that share their eWON, tag and
+// timestamp with an earlier point of the response, keeping the first,
+// and returns the number of points removed. The order of the remaining
+// points is preserved.
+func (s *SyncResponse) Dedupe() int {
+	return dedupe(s.Ewons, make(map[recordKey]bool))
+}
+
+// Dedupe removes duplicate history points from the response, like
+// SyncResponse.Dedupe.
+func (d *GetDataResponse) Dedupe() int {
+	return dedupe(d.Ewons, make(map[recordKey]bool))
+}
+
+// DedupeSyncPages is like SyncResponse.Dedupe across pages, e.g. as
+// returned by SyncAllData: points that share their eWON, tag and
+// timestamp with a point of an earlier page, or earlier in the same
+// page, are removed.
+func DedupeSyncPages(pages []*SyncResponse) int {
+	seen := make(map[recordKey]bool)
+	removed := 0
+	for _, s := range pages {
+		removed += dedupe(s.Ewons, seen)
+	}
+	return removed
+}
@@ -0,0 +1,37 @@
+package dmweb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupeSyncPages(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	pages := []*SyncResponse{
+		{Ewons: []DataEwon{{ID: 1, Tags: []DataTag{{ID: 10, History: []HistoryPoint{
+			{Date: t0, Value: 1},
+			{Date: t0.Add(time.Minute), Value: 2},
+			{Date: t0.Add(time.Minute), Value: 2},
+		}}}}}},
+		{Ewons: []DataEwon{{ID: 1, Tags: []DataTag{
+			{ID: 10, History: []HistoryPoint{
+				{Date: t0.Add(time.Minute), Value: 2},
+				{Date: t0.Add(2 * time.Minute), Value: 3},
+			}},
+			{ID: 11, History: []HistoryPoint{{Date: t0.Add(time.Minute), Value: 4}}},
+		}}}},
+	}
+
+	assert.Equal(t, 2, DedupeSyncPages(pages))
+	assert.Equal(t, []HistoryPoint{{Date: t0, Value: 1}, {Date: t0.Add(time.Minute), Value: 2}}, pages[0].Ewons[0].Tags[0].History)
+	assert.Equal(t, []HistoryPoint{{Date: t0.Add(2 * time.Minute), Value: 3}}, pages[1].Ewons[0].Tags[0].History)
+	assert.Len(t, pages[1].Ewons[0].Tags[1].History, 1)
+
+	s := &SyncResponse{Ewons: []DataEwon{{ID: 1, Tags: []DataTag{{ID: 10, History: []HistoryPoint{{Date: t0}, {Date: t0}}}}}}}
+	assert.Equal(t, 1, s.Dedupe())
+	assert.Equal(t, 0, s.Dedupe())
+	d := &GetDataResponse{Ewons: []DataEwon{{ID: 1, Tags: []DataTag{{ID: 10, History: []HistoryPoint{{Date: t0}, {Date: t0}}}}}}}
+	assert.Equal(t, 1, d.Dedupe())
+}
@@ -0,0 +1,114 @@
+package dmweb
+
+// TagConfigDiff describes how the tag configuration of two eWONs differs.
+// Tags are matched by name, since the same template deployed on several
+// eWONs yields tags with the same names but unrelated IDs.
+type TagConfigDiff struct {
+	// OnlyInA holds the tags of a without a tag of the same name in b.
+	OnlyInA Tags
+	// OnlyInB holds the tags of b without a tag of the same name in a.
+	OnlyInB Tags
+	// Changed holds the tags present in both whose data type or
+	// description differ.
+	Changed []TagConfigChange
+}
+
+// TagConfigChange is a tag present on both eWONs with a different
+// configuration.
+type TagConfigChange struct {
+	Name string
+	A    *Tag
+	B    *Tag
+}
+
+// Equal reports whether both eWONs have the same tag configuration.
+func (d TagConfigDiff) Equal() bool {
+	return len(d.OnlyInA) == 0 && len(d.OnlyInB) == 0 && len(d.Changed) == 0
+}
+
+// CompareTagConfig compares the tags of a and b, e.g. to audit that
+// devices deployed from the same template are configured consistently.
+// Results follow the tag order of a, then b.
+func CompareTagConfig(a, b *Ewon) TagConfigDiff {
+	var d TagConfigDiff
+	inB := make(map[string]*Tag, len(b.Tags))
+	for _, t := range b.Tags {
+		inB[t.Name] = t
+	}
+	inA := make(map[string]bool, len(a.Tags))
+	for _, ta := range a.Tags {
+		inA[ta.Name] = true
+		tb, ok := inB[ta.Name]
+		if !ok {
+			d.OnlyInA = append(d.OnlyInA, ta)
+			continue
+		}
+		if ta.DataType != tb.DataType || ta.Description != tb.Description {
+			d.Changed = append(d.Changed, TagConfigChange{Name: ta.Name, A: ta, B: tb})
+		}
+	}
+	for _, tb := range b.Tags {
+		if !inA[tb.Name] {
+			d.OnlyInB = append(d.OnlyInB, tb)
+		}
+	}
+	return d
+}
+
+// TagDiff describes how the tags of an eWON changed between two
+// snapshots, see DiffTags.
+type TagDiff struct {
+	// Added holds the tags of the new snapshot without a tag of the same
+	// ewonTagId in the old one.
+	Added Tags
+	// Removed holds the tags of the old snapshot without a tag of the
+	// same ewonTagId in the new one.
+	Removed Tags
+	// Changed holds the tags present in both whose name or data type
+	// differ.
+	Changed []TagChange
+}
+
+// TagChange is a tag present in both snapshots with a different name or
+// data type.
+type TagChange struct {
+	EwonTagID int
+	Old       *Tag
+	New       *Tag
+}
+
+// Empty reports whether the tags didn't change.
+func (d TagDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffTags compares two snapshots of the tags of the same eWON, e.g. to
+// alert on configuration changes. Unlike CompareTagConfig, tags are
+// matched by ewonTagId, which is stable across renames, so a renamed tag
+// is reported as changed rather than as removed and added. Results
+// follow the tag order of old, then new.
+func DiffTags(old, new *Ewon) TagDiff {
+	var d TagDiff
+	inNew := make(map[int]*Tag, len(new.Tags))
+	for _, t := range new.Tags {
+		inNew[t.EwonTagID] = t
+	}
+	inOld := make(map[int]bool, len(old.Tags))
+	for _, to := range old.Tags {
+		inOld[to.EwonTagID] = true
+		tn, ok := inNew[to.EwonTagID]
+		if !ok {
+			d.Removed = append(d.Removed, to)
+			continue
+		}
+		if to.Name != tn.Name || to.DataType != tn.DataType {
+			d.Changed = append(d.Changed, TagChange{EwonTagID: to.EwonTagID, Old: to, New: tn})
+		}
+	}
+	for _, tn := range new.Tags {
+		if !inOld[tn.EwonTagID] {
+			d.Added = append(d.Added, tn)
+		}
+	}
+	return d
+}
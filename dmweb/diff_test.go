@@ -0,0 +1,64 @@
+package dmweb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareTagConfig(t *testing.T) {
+	a := &Ewon{ID: 1, Name: "Ewon1", Tags: Tags{
+		{ID: 10, Name: "Temperature", DataType: "Float"},
+		{ID: 11, Name: "Running", DataType: "Bool"},
+		{ID: 12, Name: "Pressure", DataType: "Float"},
+	}}
+	b := &Ewon{ID: 2, Name: "Ewon2", Tags: Tags{
+		{ID: 20, Name: "Temperature", DataType: "Float"},
+		{ID: 21, Name: "Running", DataType: "Int"},
+	}}
+
+	d := CompareTagConfig(a, b)
+	assert.False(t, d.Equal())
+	if assert.Len(t, d.OnlyInA, 1) {
+		assert.Equal(t, "Pressure", d.OnlyInA[0].Name)
+	}
+	assert.Empty(t, d.OnlyInB)
+	if assert.Len(t, d.Changed, 1) {
+		assert.Equal(t, "Running", d.Changed[0].Name)
+		assert.Equal(t, DataTypeBool, d.Changed[0].A.DataType)
+		assert.Equal(t, DataTypeInt, d.Changed[0].B.DataType)
+	}
+
+	d = CompareTagConfig(b, a)
+	if assert.Len(t, d.OnlyInB, 1) {
+		assert.Equal(t, "Pressure", d.OnlyInB[0].Name)
+	}
+
+	assert.True(t, CompareTagConfig(a, a).Equal())
+}
+
+func TestDiffTags(t *testing.T) {
+	old := &Ewon{Tags: Tags{
+		{ID: 1, EwonTagID: 1, Name: "Temp", DataType: DataTypeFloat},
+		{ID: 2, EwonTagID: 2, Name: "Pressure", DataType: DataTypeFloat},
+		{ID: 3, EwonTagID: 3, Name: "Running", DataType: DataTypeBool},
+		{ID: 4, EwonTagID: 4, Name: "Count", DataType: DataTypeInt},
+	}}
+	new := &Ewon{Tags: Tags{
+		{ID: 1, EwonTagID: 1, Name: "Temp", DataType: DataTypeFloat},
+		{ID: 2, EwonTagID: 2, Name: "Pressure_bar", DataType: DataTypeFloat},
+		{ID: 4, EwonTagID: 4, Name: "Count", DataType: DataTypeFloat},
+		{ID: 5, EwonTagID: 5, Name: "Running", DataType: DataTypeBool},
+	}}
+
+	d := DiffTags(old, new)
+	assert.False(t, d.Empty())
+	assert.Equal(t, Tags{new.Tags[3]}, d.Added)
+	assert.Equal(t, Tags{old.Tags[2]}, d.Removed)
+	assert.Equal(t, []TagChange{
+		{EwonTagID: 2, Old: old.Tags[1], New: new.Tags[1]},
+		{EwonTagID: 4, Old: old.Tags[3], New: new.Tags[2]},
+	}, d.Changed)
+
+	assert.True(t, DiffTags(old, old).Empty())
+}
@@ -1,11 +1,19 @@
 package dmweb
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // DefaultBaseURL is the default URL to access the EWON service.
@@ -15,80 +23,526 @@ const DefaultBaseURL = "https://data.talk2m.com/"
 // requests to EWONs services.
 const DefaultUserAgent = "go-ewon/dmweb 0.1"
 
-// parseTime parses eWon times
-// Before firmware 13.2, the eWON is always logging data in local time.
-// As of firmware 13.2, the eWON has the option to record data using UTC timestamps.
-// affects func parseTime()?
+// DefaultReadBufferSize is the default size of the buffer response
+// bodies are read through while being decoded.
+const DefaultReadBufferSize = 32 * 1024
 
-// outgoing calls to servers should accept a Context
+// DefaultHTTPTimeout is the timeout of the HTTP client New creates when
+// none is given.
+const DefaultHTTPTimeout = 30 * time.Second
 
-var (
-	errorMissingCredentials    = errors.New("missing one or more credentials")
-	errorCouldNotParseArgument = errors.New("could not parse argument")
+// Names of the DMWeb API endpoints. By default each name is also the
+// path of the endpoint relative to the base URL, see WithEndpoint.
+const (
+	EndpointGetStatus = "getstatus"
+	EndpointGetEwons  = "getewons"
+	EndpointGetEwon   = "getewon"
+	EndpointGetData   = "getdata"
+	EndpointSyncData  = "syncdata"
+	EndpointClean     = "clean"
 )
 
+// ErrMissingCredentials is returned by New when one or more credentials
+// are empty. The returned error wraps it and names the empty fields.
+var ErrMissingCredentials = errors.New("missing one or more credentials")
+
+// ErrServiceUnavailableMaintenance is returned when the DataMailbox is
+// down for scheduled maintenance. It is detected as an HTTP 503 response
+// whose error message mentions "maintenance", case-insensitively; other
+// 503 responses are treated as transient errors. Maintenance usually
+// lasts much longer than transient unavailability, so callers should
+// back off accordingly.
+var ErrServiceUnavailableMaintenance = errors.New("service unavailable for maintenance")
+
+// ErrInvalidCredentials is returned by New when the client is created
+// WithStrictValidation and the account or developer ID is malformed. The
+// returned error wraps it and describes the problem.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrEwonNotFound matches, with errors.Is, the error returned when the
+// API reports that the requested eWON doesn't exist.
+var ErrEwonNotFound = errors.New("ewon not found")
+
+// ErrStorageFull matches, with errors.Is, the error returned when the
+// DataMailbox of the account is full and stops accepting data until
+// data is deleted, e.g. with Clean.
+var ErrStorageFull = errors.New("storage full")
+
+// ErrNilHTTPClient is returned by requests of a Client without an HTTP
+// client, e.g. a Client literal. New never leaves it nil.
+var ErrNilHTTPClient = errors.New("HTTP client is nil")
+
+// ErrIncompleteResponse is returned, wrapped with the paths of the
+// missing fields, when a response lacks required fields like the ID and
+// name of an eWON or the ID of a tag, see WithResponseValidation.
+var ErrIncompleteResponse = errors.New("incomplete response")
+
+// ErrClientClosed is returned by requests of a Client after Close.
+var ErrClientClosed = errors.New("client is closed")
+
+var errorCouldNotParseArgument = errors.New("could not parse argument")
+
 // New constructs a new DMWeb Client
-func New(h *http.Client, accountID, username, password, developerID string) (*Client, error) {
-	if accountID == "" || username == "" || password == "" || developerID == "" {
-		return nil, errorMissingCredentials
+// When h is nil, an HTTP client with a timeout of DefaultHTTPTimeout is
+// created, see WithHTTPClientTimeout.
+func New(h *http.Client, accountID, username, password, developerID string, opts ...Option) (*Client, error) {
+	if err := checkCredentials(map[string]string{
+		"accountID":   accountID,
+		"username":    username,
+		"password":    password,
+		"developerID": developerID,
+	}, "accountID", "username", "password", "developerID"); err != nil {
+		return nil, err
 	}
-	c := Client{
+	return newClient(&Client{
 		Client:    h,
 		AccountID: accountID,
 		Username:  username,
 		Password:  password,
 		DevID:     developerID,
-		baseURL:   DefaultBaseURL,
-		userAgent: DefaultUserAgent,
+		auth:      passwordAuth{},
+	}, opts)
+}
+
+// NewWithToken constructs a new DMWeb Client that authenticates with a
+// Talk2M token instead of a username and password, keeping the password
+// out of request URLs.
+// When h is nil, an HTTP client with a timeout of DefaultHTTPTimeout is
+// created, see WithHTTPClientTimeout.
+func NewWithToken(h *http.Client, accountID, token, developerID string, opts ...Option) (*Client, error) {
+	if err := checkCredentials(map[string]string{
+		"accountID":   accountID,
+		"token":       token,
+		"developerID": developerID,
+	}, "accountID", "token", "developerID"); err != nil {
+		return nil, err
+	}
+	return newClient(&Client{
+		Client:    h,
+		AccountID: accountID,
+		Token:     token,
+		DevID:     developerID,
+		auth:      tokenAuth{},
+	}, opts)
+}
+
+// NewWithCredentialProvider constructs a new DMWeb Client that asks p for
+// its credentials at the time of every request, so they don't need to be
+// kept in memory, see WithCredentialProvider.
+// When h is nil, an HTTP client with a timeout of DefaultHTTPTimeout is
+// created, see WithHTTPClientTimeout.
+func NewWithCredentialProvider(h *http.Client, p CredentialProvider, opts ...Option) (*Client, error) {
+	return newClient(&Client{Client: h}, append([]Option{WithCredentialProvider(p)}, opts...))
+}
+
+// newClient applies the defaults and opts to c.
+func newClient(c *Client, opts []Option) (*Client, error) {
+	c.baseURL = DefaultBaseURL
+	c.userAgent = DefaultUserAgent
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := c.auth.(providerAuth); c.strictValidation && !ok {
+		if err := validateCredentials(c.AccountID, c.DevID); err != nil {
+			return nil, err
+		}
+	}
+	created := c.Client == nil
+	if created {
+		timeout := c.httpTimeout
+		if timeout == 0 {
+			timeout = DefaultHTTPTimeout
+		}
+		c.Client = &http.Client{Timeout: timeout}
+	}
+	if err := c.configureTransport(created); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// checkCredentials returns ErrMissingCredentials naming every field, in
+// the given order, whose value is empty or blank.
+func checkCredentials(values map[string]string, fields ...string) error {
+	var missing []string
+	for _, f := range fields {
+		if strings.TrimSpace(values[f]) == "" {
+			missing = append(missing, f)
+		}
+	}
+	switch len(missing) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("%w: %s is empty", ErrMissingCredentials, missing[0])
+	default:
+		return fmt.Errorf("%w: %s are empty", ErrMissingCredentials, strings.Join(missing, ", "))
 	}
-	return &c, nil
 }
 
 // Request perform the actual request
-func (c *Client) Request(endpoint string, params url.Values) (*http.Response, error) {
-	req, err := http.NewRequest("GET", c.buildURL(endpoint, params), nil)
+// Cancelling ctx aborts the request, in which case the returned error
+// wraps ctx.Err(). Failed requests are retried as configured with
+// WithRetry, and every attempt is paced as configured with WithRateLimit.
+// The request, including retries and reading the body, is bounded by the
+// timeout configured with WithDefaultTimeout or WithEndpointTimeout, on
+// top of any deadline of ctx. Responses of the getstatus, getewons and
+// getewon endpoints are served from the cache configured with WithCache.
+// Extra parameters configured with WithExtraParams or
+// ContextWithExtraParams are added to params.
+func (c *Client) Request(ctx context.Context, endpoint string, params url.Values) (*http.Response, error) {
+	return c.request(ctx, endpoint, params, nil)
+}
+
+// request is like Request, adding header to the request headers.
+func (c *Client) request(ctx context.Context, endpoint string, params url.Values, header http.Header) (*http.Response, error) {
+	if c.isClosed() {
+		return nil, ErrClientClosed
+	}
+	params, err := c.mergeExtraParams(ctx, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	if c.cache != nil && cachedEndpoints[endpoint] {
+		return c.cachedRequest(ctx, endpoint, params, header)
+	}
+	return c.send(ctx, endpoint, params, header)
+}
+
+// send performs a request within the timeout of endpoint.
+func (c *Client) send(ctx context.Context, endpoint string, params url.Values, header http.Header) (*http.Response, error) {
+	if d := c.timeout(endpoint); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		res, err := c.attempt(ctx, endpoint, params, header)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		res.Body = &cancelBody{ReadCloser: res.Body, cancel: cancel}
+		return res, nil
+	}
+	return c.attempt(ctx, endpoint, params, header)
+}
+
+// attempt performs a request, retrying it as configured.
+func (c *Client) attempt(ctx context.Context, endpoint string, params url.Values, header http.Header) (*http.Response, error) {
+	if c.Client == nil {
+		return nil, ErrNilHTTPClient
+	}
+	v, err := c.buildParams(ctx, params)
 	if err != nil {
 		return nil, err
 	}
+	method := c.method(endpoint, v)
+	var errs []error
+	for attempt := 0; ; attempt++ {
+		if err := c.waitRateLimit(ctx, endpoint); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		res, err := c.do(ctx, method, endpoint, params, v, header)
+		if err == nil {
+			return res, nil
+		}
+		errs = append(errs, err)
+		wait, ok := c.retry.next(method, attempt, res, err, c.now())
+		if !ok {
+			break
+		}
+		c.log().Warnf("dmweb: %s %s failed, retrying in %s: %v", method, endpoint, wait, err)
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			errs = append(errs, ctx.Err())
+		case <-t.C:
+			continue
+		}
+		break
+	}
+	if len(errs) == 1 {
+		return nil, errs[0]
+	}
+	c.log().Errorf("dmweb: %s %s failed after %d attempts: %v", method, endpoint, len(errs), errs[len(errs)-1])
+	return nil, &RetryError{Errors: errs}
+}
+
+// isConditional reports whether req is a conditional request, to which
+// a 304 Not Modified is a successful response.
+func isConditional(req *http.Request) bool {
+	return req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != ""
+}
+
+// do performs a single attempt of a request. For responses other than
+// 200 OK, or 304 Not Modified to a conditional request, the response is
+// returned along with the error, with its body consumed.
+func (c *Client) do(ctx context.Context, method, endpoint string, params, v url.Values, header http.Header) (*http.Response, error) {
+	var (
+		req *http.Request
+		err error
+	)
+	if method == http.MethodPost {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+c.endpointPath(endpoint), strings.NewReader(v.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, c.buildURL(endpoint, v), nil)
+	}
+	if err != nil {
+		return nil, redactError(err)
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
 	req.Header.Add("User-Agent", c.userAgent)
-	res, err := c.Client.Do(req)
+	if c.noCompression {
+		req.Header.Set("Accept-Encoding", "identity")
+	} else {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	if len(c.requestHooks) > 0 {
+		r := redactRequest(req)
+		for _, h := range c.requestHooks {
+			h(r)
+		}
+	}
+	start := c.now()
+	var res *http.Response
+	if c.dryRun {
+		res = c.dryRunResponse(req, endpoint, v)
+	} else {
+		res, err = c.Client.Do(req)
+	}
+	if err != nil {
+		err = redactError(err)
+	} else {
+		decompress(res)
+		c.limitBody(res)
+		if res.StatusCode != 200 && !(res.StatusCode == http.StatusNotModified && isConditional(req)) {
+			defer res.Body.Close()
+			err = newAPIError(res)
+		}
+	}
+	elapsed := c.since(start)
 	if err != nil {
-		return res, err
+		c.log().Debugf("dmweb: %s %s failed in %s: %v", method, endpoint, elapsed, err)
+	} else {
+		c.log().Debugf("dmweb: %s %s succeeded in %s", method, endpoint, elapsed)
+	}
+	if c.metrics != nil {
+		status := 0
+		if res != nil {
+			status = res.StatusCode
+		}
+		c.metrics.ObserveRequest(endpoint, status, elapsed, err)
+	}
+	if len(c.responseHooks) > 0 {
+		r := res
+		if res != nil && res.Request != nil {
+			rc := *res
+			rc.Request = redactRequest(res.Request)
+			r = &rc
+		}
+		for _, h := range c.responseHooks {
+			h(r, elapsed, err)
+		}
 	}
-	if res.StatusCode != 200 {
-		var er errorResponse
-		err := json.NewDecoder(res.Body).Decode(&er)
+	return res, err
+}
+
+// decode decodes the JSON body of res into v, reading it through a
+// buffer of the configured size. With WithStrictDecoding, fields
+// missing from v are an error. With WithUseNumber, the exact values of
+// tags are kept.
+func (c *Client) decode(res *http.Response, v interface{}) error {
+	size := c.readBufferSize
+	if size <= 0 {
+		size = DefaultReadBufferSize
+	}
+	r := io.Reader(bufio.NewReaderSize(res.Body, size))
+	ns, useNumber := v.(numberSetter)
+	useNumber = useNumber && c.useNumber
+	var body bytes.Buffer
+	if useNumber {
+		r = io.TeeReader(r, &body)
+	}
+	dec := json.NewDecoder(r)
+	if c.strictDecoding {
+		dec.DisallowUnknownFields()
+	}
+	if fd, ok := v.(flexDecoder); ok {
+		m := fd.flexMirror()
+		err := dec.Decode(m)
+		fd.setFlex(m)
 		if err != nil {
+			return err
+		}
+	} else if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if useNumber {
+		setNumbers(ns, body.Bytes())
+	}
+	if fc, ok := v.(fieldChecker); ok && c.validateResponses {
+		return checkFields(fc)
+	}
+	return nil
+}
+
+// metaSetter is implemented by the responses that carry a ResponseMeta.
+type metaSetter interface {
+	setMeta(m *ResponseMeta)
+}
+
+// doJSON sends a request to endpoint and decodes the JSON response into a
+// new T, setting its ResponseMeta when it has one. Errors, including API
+// errors, are handled like Request does. The body is always drained
+// and closed. A 200 OK response reporting
+// success false is an APIError as well; since such responses are small,
+// this is only checked for bodies of up to 64KiB. On a request error,
+// no T is returned; on a decoding error, the partially decoded T is
+// returned along with it.
+func doJSON[T any](ctx context.Context, c *Client, endpoint string, params url.Values) (*T, error) {
+	res, err := c.Request(ctx, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res.Body)
+	var head headBuffer
+	res.Body = readCloser{Reader: io.TeeReader(res.Body, &head), Closer: res.Body}
+	var v T
+	if m, ok := any(&v).(metaSetter); ok {
+		m.setMeta(newResponseMeta(res))
+	}
+	if err := c.decode(res, &v); err != nil {
+		return &v, err
+	}
+	if !head.truncated {
+		if err := unsuccessfulError(head.b); err != nil {
 			return nil, err
 		}
-		return nil, errors.New(er.Message)
 	}
-	return res, err
+	return &v, nil
+}
+
+// maxDrainSize caps how much of the rest of a body drainAndClose reads.
+const maxDrainSize = 64 * 1024
+
+// drainAndClose reads the rest of body, up to maxDrainSize, so that the
+// connection can be reused for the next request, and closes it.
+func drainAndClose(body io.ReadCloser) {
+	io.CopyN(ioutil.Discard, body, maxDrainSize)
+	body.Close()
+}
+
+// readCloser combines a Reader and a Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
 }
 
-func (c *Client) buildURL(endpoint string, params url.Values) string {
+// buildURL returns the URL of a GET request to endpoint with the
+// parameters v, including the credentials.
+func (c *Client) buildURL(endpoint string, v url.Values) string {
+	return c.baseURL + c.endpointPath(endpoint) + "?" + v.Encode()
+}
+
+// buildParams merges the credentials with the request parameters.
+// Request parameters named like an authentication parameter are dropped,
+// so they can't shadow the credentials.
+func (c *Client) buildParams(ctx context.Context, params url.Values) (url.Values, error) {
 	v := url.Values{}
-	v.Add("t2maccount", c.AccountID)
-	v.Add("t2musername", c.Username)
-	v.Add("t2mpassword", c.Password)
-	v.Add("t2mdevid", c.DevID)
+	if err := c.authStrategy().authenticate(ctx, c, v); err != nil {
+		return nil, err
+	}
 	for p, vals := range params {
+		if reservedParams[p] {
+			continue
+		}
 		for _, val := range vals {
 			v.Add(p, val)
 		}
 	}
-	return c.baseURL + endpoint + "?" + v.Encode()
+	return v, nil
+}
+
+// maxGETQueryLength is the length of the encoded parameters above which
+// getdata and syncdata are sent as POST, to stay well below the URL
+// length limits of servers and proxies.
+const maxGETQueryLength = 2048
+
+// method returns the HTTP method to request the endpoint with. Unless
+// overridden with WithEndpointMethod, this is GET, except for getdata
+// and syncdata requests whose parameters are too long for a URL.
+func (c *Client) method(endpoint string, params url.Values) string {
+	if m, ok := c.methods[endpoint]; ok {
+		return m
+	}
+	switch endpoint {
+	case EndpointGetData, EndpointSyncData:
+		if len(params.Encode()) > maxGETQueryLength {
+			return http.MethodPost
+		}
+	}
+	return http.MethodGet
+}
+
+// endpointPath resolves an endpoint name to its path relative to the
+// base URL. Names without an override are used as is.
+func (c *Client) endpointPath(endpoint string) string {
+	if p, ok := c.endpoints[endpoint]; ok {
+		return p
+	}
+	return endpoint
 }
 
 // GetStatus returns the storage consumption of the account and of each eWON.
+// The status changes slowly, so the response is marked Unmodified when
+// it equals the previous one: requests carry the If-None-Match and
+// If-Modified-Since headers of the previous response, and a 304 Not
+// Modified returns the previous response. As Talk2M may ignore these
+// headers, a response whose body hashes the same as the previous one is
+// marked Unmodified too.
 func (c *Client) GetStatus() (*GetStatusResponse, error) {
-	res, err := c.Request("getstatus", nil)
+	return c.GetStatusContext(context.Background())
+}
+
+// GetStatusContext is like GetStatus, with ctx controlling the request.
+func (c *Client) GetStatusContext(ctx context.Context) (*GetStatusResponse, error) {
+	return c.getStatus(ctx)
+}
+
+// Ping checks connectivity and credentials with a getstatus request,
+// without decoding the response. It returns nil when the request
+// succeeds, an *APIError when the API rejects it, see IsAuthError,
+// including with a 200 OK response reporting success false, and the
+// transport error when the API can't be reached. The request always
+// reaches the API, bypassing the cache of WithCache.
+func (c *Client) Ping(ctx context.Context) error {
+	if c.isClosed() {
+		return ErrClientClosed
+	}
+	params, err := c.mergeExtraParams(ctx, EndpointGetStatus, nil)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	res, err := c.send(ctx, EndpointGetStatus, params, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	var head headBuffer
+	if _, err := io.Copy(&head, res.Body); err != nil {
+		return err
 	}
-	var s GetStatusResponse
-	err = json.NewDecoder(res.Body).Decode(&s)
-	return &s, err
+	if !head.truncated {
+		return unsuccessfulError(head.b)
+	}
+	return nil
 }
 
 // GetEwons returns all eWons
@@ -98,19 +552,19 @@ func (c *Client) GetStatus() (*GetStatusResponse, error) {
 // - its number of tags, (according to the docs, not in reality)
 // - the date of its last data upload to the Data Mailbox.
 func (c *Client) GetEwons() (Ewons, error) {
-	res, err := c.Request("getewons", nil)
-	if err != nil {
+	return c.GetEwonsContext(context.Background())
+}
+
+// GetEwonsContext is like GetEwons, with ctx controlling the request.
+func (c *Client) GetEwonsContext(ctx context.Context) (Ewons, error) {
+	es, err := doJSON[ewonsResponse](ctx, c, EndpointGetEwons, nil)
+	if es == nil {
 		return nil, err
 	}
-	var es struct {
-		Success bool
-		Ewons   Ewons
-	}
-	err = json.NewDecoder(res.Body).Decode(&es)
-	return es.Ewons, err
+	return c.filterEwons(es.Ewons), err
 }
 
-func (c *Client) getEwonByIdentifier(qp string, i interface{}) (*Ewon, error) {
+func (c *Client) getEwonByIdentifier(ctx context.Context, qp string, i interface{}) (*Ewon, error) {
 	qs := url.Values{}
 	switch i.(type) {
 	case int:
@@ -120,24 +574,52 @@ func (c *Client) getEwonByIdentifier(qp string, i interface{}) (*Ewon, error) {
 	default:
 		return nil, errorCouldNotParseArgument
 	}
-	res, err := c.Request("getewon", qs)
-	if err != nil {
+	r, err := doJSON[ewonResponse](ctx, c, EndpointGetEwon, qs)
+	if r == nil {
 		return nil, err
 	}
-	var e Ewon
-	err = json.NewDecoder(res.Body).Decode(&e)
-	return &e, err
+	return &r.Ewon, err
 }
 
 // GetEwonByID returns a single eWon by ID
 func (c *Client) GetEwonByID(id int) (*Ewon, error) {
-	return c.getEwonByIdentifier("id", id)
+	return c.GetEwonByIDContext(context.Background(), id)
+}
+
+// GetEwonByIDContext is like GetEwonByID, with ctx controlling the
+// request.
+func (c *Client) GetEwonByIDContext(ctx context.Context, id int) (*Ewon, error) {
+	return c.getEwonByIdentifier(ctx, "id", id)
 }
 
 // GetEwonByName returns a single eWon by Name
 // Name of the eWON as returned by the “getewons” API request.
+// When no eWON has that name, the returned error matches
+// ErrEwonNotFound.
 func (c *Client) GetEwonByName(name string) (*Ewon, error) {
-	return c.getEwonByIdentifier("name", name)
+	return c.GetEwonByNameContext(context.Background(), name)
+}
+
+// GetEwonByNameContext is like GetEwonByName, with ctx controlling the
+// request.
+// When the client is created WithCaseInsensitiveEwonNames and no eWON
+// has exactly the given name, the eWONs are listed and the first one
+// whose name matches case-insensitively is returned.
+func (c *Client) GetEwonByNameContext(ctx context.Context, name string) (*Ewon, error) {
+	e, err := c.getEwonByIdentifier(ctx, "name", name)
+	if err == nil || !c.ewonNameFold || !errors.Is(err, ErrEwonNotFound) {
+		return e, err
+	}
+	es, lerr := c.GetEwonsContext(ctx)
+	if lerr != nil {
+		return nil, lerr
+	}
+	for _, le := range es {
+		if strings.EqualFold(le.Name, name) {
+			return c.getEwonByIdentifier(ctx, "id", le.ID)
+		}
+	}
+	return nil, err
 }
 
 // GetData is used as a “one-shot” request to retrieve filtered
@@ -154,24 +636,108 @@ func (c *Client) GetEwonByName(name string) (*Ewon, error) {
 //     contain historical data. "fullConfig" doesn’t accept any value. It is used as is.
 //   * limit: The maximum amount of historical data returned.
 // If the size of the historical data saved in the DataMailbox exceeds this limit, only the oldest historical data will be returned and the result contains a moreDataAvailable value indicating that more data is available on the server.If the limit parameter is not used or is too high, the DataMailbox uses a limit pre-defined in the system.
+//   * moreData: The moreDataId of a previous response with more data available, to get the rest of that data.
+// Other params are forwarded as is, and from and to are sent as given.
+// As the DataMailbox silently ignores unknown parameters and returns no
+// data for timestamps it can't parse, check params with
+// ValidateDataParams, or have the client do so with
+// WithStrictValidation.
+// Use GetDataAll to follow moreDataAvailable.
 func (c *Client) GetData(params map[string]string) (*GetDataResponse, error) {
-	qs := url.Values{}
-	for k, v := range params {
-		qs.Add(k, v)
+	return c.GetDataContext(context.Background(), params)
+}
+
+// GetDataContext is like GetData, with ctx controlling the request.
+func (c *Client) GetDataContext(ctx context.Context, params map[string]string) (*GetDataResponse, error) {
+	if c.strictValidation {
+		if err := ValidateDataParams(params); err != nil {
+			return nil, err
+		}
+	}
+	return c.GetDataWithParamsContext(ctx, parseDataParams(params))
+}
+
+// GetDataWithParams is like GetData, with typed parameters.
+func (c *Client) GetDataWithParams(params GetDataParams) (*GetDataResponse, error) {
+	return c.GetDataWithParamsContext(context.Background(), params)
+}
+
+// GetDataWithParamsContext is like GetDataWithParams, with ctx
+// controlling the request.
+func (c *Client) GetDataWithParamsContext(ctx context.Context, params GetDataParams) (*GetDataResponse, error) {
+	ps, err := params.perTag()
+	if err != nil {
+		return nil, err
+	}
+	if ps == nil {
+		return c.getData(ctx, params)
+	}
+	out := &GetDataResponse{Success: true}
+	m := newDataMerger(out)
+	for _, p := range ps {
+		d, err := c.getData(ctx, p)
+		if err != nil {
+			return out, err
+		}
+		m.add(d.Ewons, nil)
+		out.MoreDataAvailable = out.MoreDataAvailable || d.MoreDataAvailable
+		if d.MoreDataAvailable && d.MoreDataID != "" {
+			if out.MoreDataIDs == nil {
+				out.MoreDataIDs = make(map[int]MoreDataID)
+			}
+			out.MoreDataIDs[*p.TagID] = d.MoreDataID
+		}
+		out.Meta = d.Meta
+	}
+	return out, nil
+}
+
+// getDataQuery returns the query of a single getdata request, along with
+// params resolved for ConfigOnly.
+func (c *Client) getDataQuery(params GetDataParams) (GetDataParams, url.Values, error) {
+	params, err := params.configOnly(c.now())
+	if err != nil {
+		return params, nil, err
 	}
-	res, err := c.Request("getdata", qs)
+	qs, err := params.values()
+	return params, qs, err
+}
+
+// getData makes a single getdata request.
+func (c *Client) getData(ctx context.Context, params GetDataParams) (*GetDataResponse, error) {
+	params, qs, err := c.getDataQuery(params)
 	if err != nil {
 		return nil, err
 	}
-	var d GetDataResponse
-	err = json.NewDecoder(res.Body).Decode(&d)
-	return &d, err
+	d, err := doJSON[GetDataResponse](ctx, c, EndpointGetData, qs)
+	if err != nil {
+		return d, err
+	}
+	d.Ewons = c.filterDataEwons(d.Ewons)
+	if params.ConfigOnly {
+		// Points logged at exactly now fall inside the empty window.
+		for i := range d.Ewons {
+			for j := range d.Ewons[i].Tags {
+				d.Ewons[i].Tags[j].History = nil
+			}
+		}
+	}
+	if c.ewonLocalTime {
+		c.localizeEwons(d.Ewons)
+	}
+	return d, nil
 }
 
 // FirstSyncData should be used the first time we're syncing data.
 // After that, use the SyncData function.
 func (c *Client) FirstSyncData() (*SyncResponse, error) {
-	return c.SyncData("", true)
+	return c.FirstSyncDataContext(context.Background())
+}
+
+// FirstSyncDataContext is like FirstSyncData, with ctx controlling the
+// request.
+func (c *Client) FirstSyncDataContext(ctx context.Context) (*SyncResponse, error) {
+	return c.SyncDataContext(ctx, "", true)
 }
 
 // SyncData is used to retrieve all the data. This service is
@@ -186,7 +752,16 @@ func (c *Client) FirstSyncData() (*SyncResponse, error) {
 //     the data linked to this transaction ID.
 //   * createTransaction: The indication to the server that a
 //     new transaction ID should be created for this request.
+//
+// SyncNewTransaction and SyncContinue name the two uses of
+// createTransaction and document what they mean for the data window.
 func (c *Client) SyncData(lastTransactionID string, createTransaction bool) (*SyncResponse, error) {
+	return c.SyncDataContext(context.Background(), lastTransactionID, createTransaction)
+}
+
+// SyncDataContext is like SyncData, with ctx controlling the request.
+// Cancelling ctx, e.g. on shutdown, aborts a long-running sync.
+func (c *Client) SyncDataContext(ctx context.Context, lastTransactionID string, createTransaction bool) (*SyncResponse, error) {
 	qs := url.Values{}
 	if lastTransactionID != "" {
 		qs.Add("lastTransactionId", lastTransactionID)
@@ -194,11 +769,40 @@ func (c *Client) SyncData(lastTransactionID string, createTransaction bool) (*Sy
 	if createTransaction {
 		qs.Add("createTransaction", "true")
 	}
-	res, err := c.Request("syncdata", qs)
+	s, err := doJSON[SyncResponse](ctx, c, EndpointSyncData, qs)
+	if err != nil && s == nil && lastTransactionID != "" && isTransactionExpired(err) {
+		err = &transactionExpiredError{id: lastTransactionID, err: err}
+		if c.autoResync {
+			c.warn(err)
+			return c.SyncDataContext(ctx, "", true)
+		}
+	}
 	if err != nil {
-		return nil, err
+		return s, err
+	}
+	s.Ewons = c.filterDataEwons(s.Ewons)
+	if c.ewonLocalTime {
+		c.localizeEwons(s.Ewons)
+	}
+	return s, nil
+}
+
+// Clean deletes historical data from the DataMailbox, e.g. to stay under
+// the storage quota of the account. Without params, all data of the
+// account is deleted. params can restrict this to a single eWON, and/or
+// to the data up to and including a transaction returned by SyncData.
+func (c *Client) Clean(params CleanParams) (*CleanResponse, error) {
+	return c.CleanContext(context.Background(), params)
+}
+
+// CleanContext is like Clean, with ctx controlling the request.
+func (c *Client) CleanContext(ctx context.Context, params CleanParams) (*CleanResponse, error) {
+	qs := url.Values{}
+	if params.EwonID != nil {
+		qs.Add("ewonId", strconv.Itoa(*params.EwonID))
+	}
+	if params.TransactionID != "" {
+		qs.Add("transactionId", params.TransactionID)
 	}
-	var s SyncResponse
-	err = json.NewDecoder(res.Body).Decode(&s)
-	return &s, err
+	return doJSON[CleanResponse](ctx, c, EndpointClean, qs)
 }
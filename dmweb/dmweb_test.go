@@ -2,8 +2,14 @@ package dmweb
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -32,15 +38,15 @@ func TestNew(t *testing.T) {
 		did string
 		err error
 	}{
-		{"", "", "", "", errorMissingCredentials},
-		{"accountid", "username", "password", "", errorMissingCredentials},
+		{"", "", "", "", ErrMissingCredentials},
+		{"accountid", "username", "password", "", ErrMissingCredentials},
 		{"accountid", "username", "password", "did", nil},
 	}
 
 	for _, table := range tables {
 		c, err := New(h, table.aid, table.u, table.p, table.did)
 		if err != nil {
-			assert.Equal(t, table.err, err)
+			assert.True(t, errors.Is(err, table.err))
 		} else {
 			assert.Equal(t, c.baseURL, DefaultBaseURL)
 			assert.Equal(t, c.userAgent, DefaultUserAgent)
@@ -48,6 +54,28 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewMissingCredentials(t *testing.T) {
+	tables := []struct {
+		aid string
+		u   string
+		p   string
+		did string
+		msg string
+	}{
+		{"", "username", "password", "did", "missing one or more credentials: accountID is empty"},
+		{"accountid", "", "password", "did", "missing one or more credentials: username is empty"},
+		{"accountid", "username", "  ", "did", "missing one or more credentials: password is empty"},
+		{"accountid", "username", "password", "", "missing one or more credentials: developerID is empty"},
+		{"", "username", "", "did", "missing one or more credentials: accountID, password are empty"},
+	}
+
+	for _, table := range tables {
+		_, err := New(&http.Client{}, table.aid, table.u, table.p, table.did)
+		assert.True(t, errors.Is(err, ErrMissingCredentials))
+		assert.EqualError(t, err, table.msg)
+	}
+}
+
 func TestRequest(t *testing.T) {
 
 	tables := []struct {
@@ -77,7 +105,7 @@ func TestRequest(t *testing.T) {
 			DevID:     table.did,
 			baseURL:   DefaultBaseURL,
 			userAgent: DefaultUserAgent}
-		res, err := c.Request(table.endpoint, nil)
+		res, err := c.Request(context.Background(), table.endpoint, nil)
 		assert.NoError(t, err)
 		assert.IsType(t, &http.Response{}, res)
 		bb, _ := ioutil.ReadAll(res.Body)
@@ -268,6 +296,26 @@ func TestGetEwonByID(t *testing.T) {
 	assert.Equal(t, t1, e.LastSynchroDate)
 	assert.Equal(t, "Random_Metric", e.Tags[0].Name)
 
+	// The success flag is decoded along with the eWON, also strictly.
+	c.Client = NewTestClient(func(req *http.Request) *http.Response {
+		return jsonResponse(200, `{"success":true,"id":123456,"name":"Ewon1","tags":[{"id":98765,"name":"Count","value":9007199254740993}]}`)
+	})
+	for _, o := range []Option{WithStrictDecoding(), WithUseNumber(), WithResponseValidation()} {
+		assert.NoError(t, o(c))
+	}
+	e, err = c.GetEwonByID(123456)
+	if assert.NoError(t, err) && assert.Len(t, e.Tags, 1) {
+		n, err := e.Tags[0].Int64Value()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(9007199254740993), n)
+	}
+	c.Client = NewTestClient(func(req *http.Request) *http.Response {
+		return jsonResponse(200, `{"success":true,"name":"Ewon1"}`)
+	})
+	_, err = c.GetEwonByID(123456)
+	assert.True(t, errors.Is(err, ErrIncompleteResponse))
+	c.strictDecoding, c.useNumber, c.validateResponses = false, false, false
+
 	// Test unknown EwonID
 	c.Client = NewTestClient(func(req *http.Request) *http.Response {
 		assert.Equal(t, "go-ewon/dmweb 0.1", req.Header.Get("User-Agent"))
@@ -527,3 +575,331 @@ func TestSyncData(t *testing.T) {
 	assert.Equal(t, "987654", s.TransactionID)
 
 }
+
+// chunkedReader mimics a slow link by returning at most one TCP segment
+// worth of data per Read.
+type chunkedReader struct {
+	r io.Reader
+}
+
+func (c chunkedReader) Read(p []byte) (int, error) {
+	if len(p) > 1460 {
+		p = p[:1460]
+	}
+	return c.r.Read(p)
+}
+
+func largeSyncPayload(points int) []byte {
+	var b bytes.Buffer
+	b.WriteString(`{"success":true,"transactionId":"1","moreDataAvailable":false,"ewons":[{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"TAG","history":[`)
+	for i := 0; i < points; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `{"date":"2018-11-08T14:17:58Z","dataType":"Float","value":%d.25,"quality":"good"}`, i)
+	}
+	b.WriteString(`]}]}]}`)
+	return b.Bytes()
+}
+
+func BenchmarkDecodeReadBufferSize(b *testing.B) {
+	payload := largeSyncPayload(20000)
+	for _, size := range []int{512, 4096, DefaultReadBufferSize, 256 * 1024} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			c := &Client{readBufferSize: size}
+			b.SetBytes(int64(len(payload)))
+			for i := 0; i < b.N; i++ {
+				res := &http.Response{Body: ioutil.NopCloser(chunkedReader{bytes.NewReader(payload)})}
+				var s SyncResponse
+				if err := c.decode(res, &s); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestRequestMaintenance(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(503, `{"success":false,"code":503,"message":"The DataMailbox is under scheduled Maintenance"}`)
+	})
+	_, err := c.GetStatus()
+	assert.True(t, errors.Is(err, ErrServiceUnavailableMaintenance))
+	assert.Contains(t, err.Error(), "scheduled Maintenance")
+
+	c = newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(503, `{"success":false,"code":503,"message":"Service unavailable"}`)
+	})
+	_, err = c.GetStatus()
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrServiceUnavailableMaintenance))
+}
+
+func TestGetDataInvalidTimestamp(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		t.Errorf("unexpected request %s", req.URL)
+		return jsonResponse(200, `{"success":true}`)
+	})
+	assert.NoError(t, WithStrictValidation()(c))
+	_, err := c.GetData(map[string]string{"from": "2018-11-08T14:17:58Z", "to": "08/11/2018 14:17"})
+	assert.True(t, errors.Is(err, errorCouldNotParseArgument))
+	assert.Contains(t, err.Error(), "parameter to")
+}
+
+type transportFunc func(r *http.Request) (*http.Response, error)
+
+func (f transportFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestSyncDataContextCancel(t *testing.T) {
+	c := newTestDMWebClient(nil)
+	c.Client.Transport = transportFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err := c.SyncDataContext(ctx, "456789", true)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestGetDataFractionalValues(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(200, `{
+			"success": true,
+			"moreDataAvailable": false,
+			"ewons": [{
+				"id": 508238,
+				"name": "ltn_flexy",
+				"tags": [{
+					"id": 780591,
+					"name": "TAG_2",
+					"dataType": "Float",
+					"value": 1234.4567,
+					"quality": "good",
+					"history": [{
+						"date": "2018-11-08T14:17:58Z",
+						"dataType": "Float",
+						"quality": "initialGood",
+						"value": 0.25
+					}]
+				}]
+			}]
+		}`)
+	})
+
+	d, err := c.GetData(nil)
+	assert.NoError(t, err)
+	tag := d.Ewons[0].Tags[0]
+	assert.Equal(t, 1234.4567, tag.Value)
+	if assert.Len(t, tag.History, 1) {
+		assert.Equal(t, 0.25, tag.History[0].Value)
+		assert.Equal(t, DataTypeFloat, tag.History[0].DataType)
+		assert.Equal(t, QualityInitialGood, tag.History[0].Quality)
+	}
+}
+
+func TestClean(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "/clean", req.URL.Path)
+		assert.Equal(t, "508238", req.URL.Query().Get("ewonId"))
+		assert.Equal(t, "456789", req.URL.Query().Get("transactionId"))
+		return jsonResponse(200, `{"success":true}`)
+	})
+	ewonID := 508238
+	r, err := c.Clean(CleanParams{EwonID: &ewonID, TransactionID: "456789"})
+	assert.NoError(t, err)
+	assert.True(t, r.Success)
+
+	c = newTestDMWebClient(func(req *http.Request) *http.Response {
+		_, ok := req.URL.Query()["ewonId"]
+		assert.False(t, ok)
+		return jsonResponse(401, `{"success":false,"code":401,"message":"Invalid credentials"}`)
+	})
+	_, err = c.Clean(CleanParams{})
+	assert.EqualError(t, err, "Invalid credentials")
+}
+
+func TestPing(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "/getstatus", req.URL.Path)
+		return jsonResponse(200, `{"historyCount":1,"ewonsCount":0,"ewons":[]}`)
+	})
+	assert.NoError(t, c.Ping(context.Background()))
+
+	c = newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(401, `{"success":false,"code":401,"message":"Invalid credentials"}`)
+	})
+	err := c.Ping(context.Background())
+	assert.True(t, IsAuthError(err))
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+
+	c = newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(200, `{"success":false,"code":401,"message":"Invalid credentials"}`)
+	})
+	assert.True(t, IsAuthError(c.Ping(context.Background())))
+}
+
+func TestPingBypassesCache(t *testing.T) {
+	n := 0
+	c, err := New(NewTestClient(func(req *http.Request) *http.Response {
+		n++
+		if n > 1 {
+			return nil
+		}
+		return jsonResponse(200, `{"success":true,"historyCount":1,"ewonsCount":0,"ewons":[]}`)
+	}), "aid", "username", "password", "devid", WithCache(time.Minute))
+	assert.NoError(t, err)
+	_, err = c.GetStatus()
+	assert.NoError(t, err)
+	assert.Error(t, c.Ping(context.Background()))
+	assert.Equal(t, 2, n)
+}
+
+func TestResponseMeta(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		res := jsonResponse(200, `{"success":true,"historyCount":0,"ewonsCount":0,"ewons":[]}`)
+		res.Header.Set("X-RateLimit-Remaining", "42")
+		return res
+	})
+
+	s, err := c.GetStatus()
+	if assert.NoError(t, err) && assert.NotNil(t, s.Meta) {
+		assert.Equal(t, 200, s.Meta.StatusCode)
+		assert.Equal(t, "42", s.Meta.Header.Get("X-RateLimit-Remaining"))
+	}
+	d, err := c.GetDataWithParams(GetDataParams{})
+	if assert.NoError(t, err) && assert.NotNil(t, d.Meta) {
+		assert.Equal(t, "42", d.Meta.Header.Get("X-RateLimit-Remaining"))
+	}
+	sd, err := c.FirstSyncData()
+	if assert.NoError(t, err) && assert.NotNil(t, sd.Meta) {
+		assert.Equal(t, "application/json;charset=UTF-8", sd.Meta.Header.Get("Content-Type"))
+	}
+	r, err := c.Clean(CleanParams{})
+	if assert.NoError(t, err) {
+		assert.NotNil(t, r.Meta)
+	}
+}
+
+// countingBody counts the bytes read from a body and whether it was
+// closed.
+type countingBody struct {
+	r      io.Reader
+	n      int
+	closed bool
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.n += n
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestDoJSONClosesBody(t *testing.T) {
+	var bodies []*countingBody
+	var payloads []string
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		b := &countingBody{r: strings.NewReader(payloads[len(bodies)])}
+		bodies = append(bodies, b)
+		res := jsonResponse(200, "")
+		res.Body = b
+		return res
+	})
+
+	payloads = []string{
+		`{"historyCount":1,"ewonsCount":0,"ewons":[]}` + strings.Repeat(" ", 8192),
+		`{"historyCount":"x"}`,
+		`{"historyCount":`,
+	}
+	_, err := c.GetStatus()
+	assert.NoError(t, err)
+	_, err = c.GetStatus()
+	assert.Error(t, err)
+	_, err = c.GetStatus()
+	assert.Error(t, err)
+	for i, b := range bodies {
+		assert.True(t, b.closed, "body %d not closed", i)
+		assert.Equal(t, len(payloads[i]), b.n, "body %d not drained", i)
+	}
+}
+
+func TestNilHTTPClient(t *testing.T) {
+	c := &Client{AccountID: "aid", Username: "username", Password: "password", DevID: "devid"}
+	_, err := c.GetStatus()
+	assert.Equal(t, ErrNilHTTPClient, err)
+
+	c, err = New(nil, "aid", "username", "password", "devid")
+	assert.NoError(t, err)
+	assert.NotNil(t, c.Client)
+}
+
+func TestDoJSON(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		switch req.URL.Path {
+		case "/custom":
+			return jsonResponse(200, `{"success":true,"value":3}`)
+		case "/broken":
+			return jsonResponse(200, `{"success":true,"value":"x"}`)
+		}
+		return jsonResponse(500, `{"success":false,"code":500,"message":"error"}`)
+	})
+	type custom struct {
+		Success bool
+		Value   int
+	}
+	v, err := doJSON[custom](context.Background(), c, "custom", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &custom{Success: true, Value: 3}, v)
+
+	v, err = doJSON[custom](context.Background(), c, "broken", nil)
+	assert.Error(t, err)
+	assert.True(t, v.Success)
+
+	v, err = doJSON[custom](context.Background(), c, "failing", nil)
+	assert.Nil(t, v)
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+}
+
+func TestGetEwonByNameCaseInsensitive(t *testing.T) {
+	var paths []string
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		paths = append(paths, req.URL.Path+"?"+req.URL.Query().Get("name")+req.URL.Query().Get("id"))
+		switch {
+		case req.URL.Path == "/getewons":
+			return jsonResponse(200, `{"success":true,"ewons":[{"id":1,"name":"Paris"},{"id":2,"name":"Brussels"}]}`)
+		case req.URL.Query().Get("id") == "2":
+			return jsonResponse(200, `{"success":true,"id":2,"name":"Brussels"}`)
+		}
+		return jsonResponse(404, `{"success":false,"code":404,"message":"No eWON found for name '`+req.URL.Query().Get("name")+`'"}`)
+	})
+
+	_, err := c.GetEwonByName("brussels")
+	assert.True(t, errors.Is(err, ErrEwonNotFound))
+	assert.Equal(t, []string{"/getewon?brussels"}, paths)
+
+	assert.NoError(t, WithCaseInsensitiveEwonNames()(c))
+	paths = nil
+	e, err := c.GetEwonByName("brussels")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, e.ID)
+	}
+	assert.Equal(t, []string{"/getewon?brussels", "/getewons?", "/getewon?2"}, paths)
+
+	_, err = c.GetEwonByName("london")
+	assert.True(t, errors.Is(err, ErrEwonNotFound))
+	assert.EqualError(t, err, "No eWON found for name 'london'")
+}
@@ -0,0 +1,86 @@
+/*
+Package dmwebtest provides utilities for testing code that uses the
+dmweb client, without making requests to Talk2M.
+
+NewMockClient returns a client whose requests are served by a handler in
+memory. Fixtures serves canned responses, modeled on real DMWeb payloads,
+for every endpoint:
+
+	c := dmwebtest.NewMockClient(dmwebtest.Fixtures())
+	es, err := c.GetEwons()
+*/
+package dmwebtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path"
+
+	"github.com/factrylabs/go-ewon/dmweb"
+)
+
+// Credentials NewMockClient creates its client with.
+const (
+	AccountID   = "account"
+	Username    = "username"
+	Password    = "password"
+	DeveloperID = "00000000-0000-0000-0000-000000000000"
+)
+
+// handlerTransport serves requests with a handler, in memory.
+type handlerTransport struct {
+	h http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.h.ServeHTTP(rec, req)
+	res := rec.Result()
+	res.Request = req
+	return res, nil
+}
+
+// NewMockClient returns a client, created with New and opts, whose
+// requests are served by handler instead of Talk2M. The path of the
+// requests is the endpoint name, e.g. "/getewons", and the parameters
+// are in the query or, for POST requests, the form.
+// It panics if opts are invalid.
+func NewMockClient(handler http.HandlerFunc, opts ...dmweb.Option) *dmweb.Client {
+	h := &http.Client{Transport: handlerTransport{handler}}
+	c, err := dmweb.New(h, AccountID, Username, Password, DeveloperID, opts...)
+	if err != nil {
+		panic("dmwebtest: " + err.Error())
+	}
+	return c
+}
+
+// JSON returns a handler that responds with status and the JSON body.
+func JSON(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+// Fixtures returns a handler that responds to every endpoint with its
+// fixture, e.g. GetEwonsJSON for getewons, and with NotFoundJSON to
+// unknown paths.
+func Fixtures() http.HandlerFunc {
+	fixtures := map[string]string{
+		dmweb.EndpointGetStatus: GetStatusJSON,
+		dmweb.EndpointGetEwons:  GetEwonsJSON,
+		dmweb.EndpointGetEwon:   GetEwonJSON,
+		dmweb.EndpointGetData:   GetDataJSON,
+		dmweb.EndpointSyncData:  SyncDataJSON,
+		dmweb.EndpointClean:     CleanJSON,
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := fixtures[path.Base(r.URL.Path)]
+		if !ok {
+			JSON(http.StatusNotFound, NotFoundJSON)(w, r)
+			return
+		}
+		JSON(http.StatusOK, body)(w, r)
+	}
+}
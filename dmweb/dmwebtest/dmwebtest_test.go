@@ -0,0 +1,75 @@
+package dmwebtest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/factrylabs/go-ewon/dmweb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixtures(t *testing.T) {
+	c := NewMockClient(Fixtures())
+
+	s, err := c.GetStatus()
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, s.EwonsCount)
+	}
+	es, err := c.GetEwons()
+	if assert.NoError(t, err) && assert.Len(t, es, 2) {
+		assert.Equal(t, "ltn_flexy", es[1].Name)
+	}
+	e, err := c.GetEwonByID(123456)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Random_Metric", e.Tags[0].Name)
+	}
+	d, err := c.GetDataWithParams(dmweb.GetDataParams{})
+	if assert.NoError(t, err) {
+		assert.Len(t, d.Records(), 3)
+	}
+	sd, err := c.FirstSyncData()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "456789", sd.TransactionID)
+		assert.Len(t, sd.Records(), 3)
+	}
+	_, err = c.Clean(dmweb.CleanParams{})
+	assert.NoError(t, err)
+}
+
+func TestNewMockClient(t *testing.T) {
+	c := NewMockClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/getstatus", r.URL.Path)
+		assert.Equal(t, Password, r.URL.Query().Get("t2mpassword"))
+		JSON(http.StatusUnauthorized, InvalidCredentialsJSON)(w, r)
+	})
+	_, err := c.GetStatus()
+	assert.True(t, dmweb.IsAuthError(err))
+
+	assert.Panics(t, func() { NewMockClient(Fixtures(), dmweb.WithReadBufferSize(0)) })
+}
+
+func TestStringValuesFixture(t *testing.T) {
+	want, err := NewMockClient(JSON(http.StatusOK, SyncDataJSON)).FirstSyncData()
+	assert.NoError(t, err)
+	got, err := NewMockClient(JSON(http.StatusOK, SyncDataStringValuesJSON)).FirstSyncData()
+	assert.NoError(t, err)
+	assert.Equal(t, want.Records(), got.Records())
+	assert.Equal(t, 1510.0, got.Ewons[0].Tags[0].Value)
+}
+
+func TestFixturesStrictDecoding(t *testing.T) {
+	c := NewMockClient(Fixtures(), dmweb.WithStrictDecoding())
+
+	_, err := c.GetStatus()
+	assert.NoError(t, err)
+	_, err = c.GetEwons()
+	assert.NoError(t, err)
+	_, err = c.GetEwonByID(123456)
+	assert.NoError(t, err)
+	_, err = c.GetDataWithParams(dmweb.GetDataParams{})
+	assert.NoError(t, err)
+	_, err = c.FirstSyncData()
+	assert.NoError(t, err)
+	_, err = c.Clean(dmweb.CleanParams{})
+	assert.NoError(t, err)
+}
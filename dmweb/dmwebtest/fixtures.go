@@ -0,0 +1,154 @@
+package dmwebtest
+
+// Fixtures of DMWeb responses, modeled on real payloads of an account
+// with the eWONs "Ewon1" (ID 123456) and "ltn_flexy" (ID 508238).
+const (
+	// GetStatusJSON is a response to getstatus.
+	GetStatusJSON = `{
+	"success": true,
+	"historyCount": 20732,
+	"ewonsCount": 2,
+	"ewons": [{
+		"id": 123456,
+		"name": "Ewon1",
+		"historyCount": 2702,
+		"firstHistoryDate": "2015-07-16T16:04:25Z",
+		"lastHistoryDate": "2015-07-17T17:43:36Z"
+	}, {
+		"id": 508238,
+		"name": "ltn_flexy",
+		"historyCount": 18030,
+		"firstHistoryDate": "2015-08-24T08:56:44Z",
+		"lastHistoryDate": "2018-11-09T09:47:00Z"
+	}]
+}`
+
+	// GetEwonsJSON is a response to getewons.
+	GetEwonsJSON = `{
+	"success": true,
+	"ewons": [{
+		"id": 123456,
+		"name": "Ewon1",
+		"lastSynchroDate": "2018-06-05T12:49:27Z"
+	}, {
+		"id": 508238,
+		"name": "ltn_flexy",
+		"timeZone": "Europe/Brussels",
+		"lastSynchroDate": "2018-11-09T09:47:00Z"
+	}]
+}`
+
+	// GetEwonJSON is a response to getewon for Ewon1.
+	GetEwonJSON = `{
+	"success": true,
+	"id": 123456,
+	"name": "Ewon1",
+	"tags": [{
+		"id": 98765,
+		"name": "Random_Metric",
+		"dataType": "Float",
+		"description": "",
+		"alarmHint": "",
+		"value": 1234.4567,
+		"quality": "good",
+		"ewonTagId": 10
+	}],
+	"lastSynchroDate": "2018-06-05T12:49:27Z"
+}`
+
+	// GetDataJSON is a response to getdata for ltn_flexy.
+	GetDataJSON = `{
+	"success": true,
+	"moreDataAvailable": false,
+	"ewons": [{
+		"id": 508238,
+		"name": "ltn_flexy",
+		"tags": [{
+			"id": 780591,
+			"name": "TAG_2",
+			"dataType": "Float",
+			"description": "",
+			"alarmHint": "",
+			"value": 1510,
+			"quality": "good",
+			"ewonTagId": 2,
+			"history": [
+				{"date": "2018-11-08T14:17:58Z", "quality": "initialGood", "value": 0},
+				{"date": "2018-11-08T14:18:00Z", "quality": "good", "value": 1500},
+				{"date": "2018-11-08T14:18:02Z", "quality": "good", "value": 1510}
+			]
+		}],
+		"lastSynchroDate": "2018-11-09T09:47:00Z",
+		"timeZone": "Europe/Brussels"
+	}]
+}`
+
+	// SyncDataJSON is a response to syncdata for ltn_flexy, the last
+	// page of transaction 456789.
+	SyncDataJSON = `{
+	"success": true,
+	"transactionId": "456789",
+	"moreDataAvailable": false,
+	"ewons": [{
+		"id": 508238,
+		"name": "ltn_flexy",
+		"tags": [{
+			"id": 780591,
+			"name": "TAG_2",
+			"dataType": "Float",
+			"description": "",
+			"alarmHint": "",
+			"value": 1510,
+			"quality": "good",
+			"ewonTagId": 2,
+			"history": [
+				{"date": "2018-11-08T14:17:58Z", "dataType": "Float", "quality": "initialGood", "value": 0},
+				{"date": "2018-11-08T14:18:00Z", "dataType": "Float", "quality": "good", "value": 1500},
+				{"date": "2018-11-08T14:18:02Z", "dataType": "Float", "quality": "good", "value": 1510}
+			]
+		}],
+		"lastSynchroDate": "2018-11-09T09:47:00Z",
+		"timeZone": "Europe/Brussels"
+	}]
+}`
+
+	// SyncDataStringValuesJSON is like SyncDataJSON, as written by
+	// firmware versions that encode the values as strings.
+	SyncDataStringValuesJSON = `{
+	"success": true,
+	"transactionId": "456789",
+	"moreDataAvailable": false,
+	"ewons": [{
+		"id": 508238,
+		"name": "ltn_flexy",
+		"tags": [{
+			"id": 780591,
+			"name": "TAG_2",
+			"dataType": "Float",
+			"description": "",
+			"alarmHint": "",
+			"value": "1510",
+			"quality": "good",
+			"ewonTagId": 2,
+			"history": [
+				{"date": "2018-11-08T14:17:58Z", "dataType": "Float", "quality": "initialGood", "value": "0"},
+				{"date": "2018-11-08T14:18:00Z", "dataType": "Float", "quality": "good", "value": "1500"},
+				{"date": "2018-11-08T14:18:02Z", "dataType": "Float", "quality": "good", "value": "1510"}
+			]
+		}],
+		"lastSynchroDate": "2018-11-09T09:47:00Z",
+		"timeZone": "Europe/Brussels"
+	}]
+}`
+
+	// CleanJSON is a response to clean.
+	CleanJSON = `{"success": true}`
+
+	// InvalidCredentialsJSON is the error response to a request with
+	// invalid credentials, with status 401.
+	InvalidCredentialsJSON = `{"success": false, "code": 401, "message": "Invalid credentials"}`
+
+	// NotFoundJSON is the error response to a request for an unknown
+	// eWON, with status 404.
+	NotFoundJSON = `{"success": false, "code": 404, "message": "No eWON found"}`
+)
@@ -2,6 +2,9 @@
 based on rg-0005-00-en-reference-guide-for-dmweb-api.pdf
 
 The document's argumentation of using GET vs. POST requests for security
-makes no sense. We opt for GET requests to confirm with REST.
+makes no sense. We opt for GET requests to confirm with REST, except for
+getdata and syncdata requests whose parameters are too long to fit in a
+URL, which are sent as POST. See WithEndpointMethod to change this per
+endpoint.
 */
 package dmweb
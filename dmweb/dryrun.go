@@ -0,0 +1,36 @@
+package dmweb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// dryRunBodies are the empty but valid responses of the endpoints in dry
+// run mode, see WithDryRun.
+var dryRunBodies = map[string]string{
+	EndpointGetStatus: `{"success":true,"historyCount":0,"ewonsCount":0,"ewons":[]}`,
+	EndpointGetEwons:  `{"success":true,"ewons":[]}`,
+	EndpointGetEwon:   `{"success":true,"tags":[]}`,
+	EndpointGetData:   `{"success":true,"moreDataAvailable":false,"ewons":[]}`,
+	EndpointSyncData:  `{"success":true,"transactionId":"0","moreDataAvailable":false,"ewons":[]}`,
+	EndpointClean:     `{"success":true}`,
+}
+
+// dryRunResponse logs the request that would be sent to endpoint with the
+// parameters v and returns the canned response of endpoint.
+func (c *Client) dryRunResponse(req *http.Request, endpoint string, v url.Values) *http.Response {
+	c.log().Debugf("dmweb: dry run: %s %s", req.Method, RedactURL(c.buildURL(endpoint, v)))
+	body, ok := dryRunBodies[endpoint]
+	if !ok {
+		body = `{"success":true}`
+	}
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		Request:    req,
+	}
+}
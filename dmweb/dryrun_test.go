@@ -0,0 +1,50 @@
+package dmweb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDryRun(t *testing.T) {
+	l := &testLogger{}
+	c, err := New(NewTestClient(func(req *http.Request) *http.Response {
+		t.Fatal("request sent in dry run mode")
+		return nil
+	}), "aid", "username", "password", "devid", WithDryRun(), WithLogger(l))
+	assert.NoError(t, err)
+
+	s, err := c.GetStatus()
+	assert.NoError(t, err)
+	assert.Empty(t, s.Ewons)
+	es, err := c.GetEwons()
+	assert.NoError(t, err)
+	assert.Empty(t, es)
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := c.GetDataAll(context.Background(), GetDataParams{From: &from})
+	assert.NoError(t, err)
+	assert.Empty(t, d.Ewons)
+	sd, err := c.SyncAllData("")
+	assert.NoError(t, err)
+	assert.Len(t, sd, 1)
+
+	if assert.NotEmpty(t, l.lines) {
+		assert.Equal(t, "DEBUG dmweb: dry run: GET https://data.talk2m.com/getstatus?t2maccount=aid&t2mdevid=devid&t2mpassword=***&t2musername=***", l.lines[0])
+	}
+}
+
+func TestWithDryRunStrictDecoding(t *testing.T) {
+	c, err := New(nil, "aid", "username", "password", "devid", WithDryRun(), WithStrictDecoding())
+	assert.NoError(t, err)
+	_, err = c.GetStatus()
+	assert.NoError(t, err)
+	_, err = c.GetEwons()
+	assert.NoError(t, err)
+	_, err = c.GetEwonByID(1)
+	assert.NoError(t, err)
+	_, err = c.FirstSyncData()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,167 @@
+package dmweb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// APIError is the error returned when the DMWeb API responds with an
+// error.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Code is the error code in the response body, which usually equals
+	// the HTTP status code.
+	Code int
+	// Message is the error message in the response body. When the body
+	// isn't a JSON error, e.g. the HTML error page of a gateway, it is
+	// the status followed by the start of the body.
+	Message string
+	// Body is the start of the body when it isn't a JSON error.
+	Body string
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// maxErrorBodySize caps how much of an error response is read, and
+// maxErrorSnippet how much of a non-JSON body ends up in the APIError.
+const (
+	maxErrorBodySize = 64 * 1024
+	maxErrorSnippet  = 256
+)
+
+// newAPIError returns the error for a response other than 200 OK. It
+// reads and decodes the JSON error in the body, falling back to the
+// status and a snippet of the body if that fails.
+func newAPIError(res *http.Response) error {
+	b, err := ioutil.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+	if err != nil {
+		return err
+	}
+	var er errorResponse
+	if err := json.Unmarshal(b, &er); err == nil && er.Message != "" {
+		return &APIError{StatusCode: res.StatusCode, Code: er.Code, Message: er.Message}
+	}
+	e := &APIError{StatusCode: res.StatusCode, Code: res.StatusCode, Message: res.Status}
+	if e.Message == "" {
+		e.Message = fmt.Sprintf("%d %s", res.StatusCode, http.StatusText(res.StatusCode))
+	}
+	body := strings.TrimSpace(string(b))
+	if len(body) > maxErrorSnippet {
+		body = strings.ToValidUTF8(body[:maxErrorSnippet], "") + "..."
+	}
+	if body != "" {
+		e.Body = body
+		e.Message += ": " + body
+	}
+	return e
+}
+
+// Is makes errors.Is match the sentinel errors this package detects from
+// API errors: ErrServiceUnavailableMaintenance, ErrEwonNotFound and
+// ErrStorageFull.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrServiceUnavailableMaintenance:
+		return e.StatusCode == http.StatusServiceUnavailable &&
+			strings.Contains(strings.ToLower(e.Message), "maintenance")
+	case ErrEwonNotFound:
+		return e.StatusCode == http.StatusNotFound &&
+			strings.Contains(strings.ToLower(e.Message), "ewon")
+	case ErrStorageFull:
+		return e.Code == http.StatusInsufficientStorage || e.StatusCode == http.StatusInsufficientStorage ||
+			isStorageFullMessage(e.Message)
+	}
+	return false
+}
+
+// isStorageFullMessage reports whether an error message says that the
+// storage of the account is full.
+func isStorageFullMessage(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "storage") && (strings.Contains(msg, "full") || strings.Contains(msg, "quota"))
+}
+
+// headBuffer keeps the first maxErrorBodySize bytes written to it.
+type headBuffer struct {
+	b         []byte
+	truncated bool
+}
+
+func (h *headBuffer) Write(p []byte) (int, error) {
+	if room := maxErrorBodySize - len(h.b); len(p) > room {
+		h.b = append(h.b, p[:room]...)
+		h.truncated = true
+	} else {
+		h.b = append(h.b, p...)
+	}
+	return len(p), nil
+}
+
+// unsuccessfulError returns the APIError for the body b of a 200 OK
+// response that reports success false, or nil when b doesn't.
+func unsuccessfulError(b []byte) error {
+	var er struct {
+		Success *bool  `json:"success"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(b, &er) != nil || er.Success == nil || *er.Success {
+		return nil
+	}
+	e := &APIError{StatusCode: http.StatusOK, Code: er.Code, Message: er.Message}
+	if e.Message == "" {
+		e.Message = "request unsuccessful"
+	}
+	return e
+}
+
+// IsAuthError reports whether err is, or wraps, an APIError for invalid
+// credentials.
+func IsAuthError(err error) bool {
+	var e *APIError
+	return errors.As(err, &e) && (e.Code == http.StatusUnauthorized || e.StatusCode == http.StatusUnauthorized)
+}
+
+// AccountErrors collects the errors of a call that fans out over multiple
+// Talk2M accounts, see MultiClient, keyed by account ID. Only accounts
+// that failed are present.
+type AccountErrors map[string]error
+
+func (e AccountErrors) Error() string {
+	ids := make([]string, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	msgs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		msgs = append(msgs, fmt.Sprintf("account %s: %v", id, e[id]))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// EwonErrors collects the errors of a call that fans out over multiple
+// eWONs, keyed by eWON ID. Only eWONs that failed are present.
+type EwonErrors map[int]error
+
+func (e EwonErrors) Error() string {
+	ids := make([]int, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	msgs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		msgs = append(msgs, fmt.Sprintf("ewon %d: %v", id, e[id]))
+	}
+	return strings.Join(msgs, "; ")
+}
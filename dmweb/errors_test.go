@@ -0,0 +1,99 @@
+package dmweb
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(404, `{"success":false,"code":404,"message":"No eWON found for id '987654'"}`)
+	})
+	_, err := c.GetEwonByID(987654)
+	var e *APIError
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, 404, e.StatusCode)
+		assert.Equal(t, 404, e.Code)
+		assert.Equal(t, "No eWON found for id '987654'", e.Message)
+	}
+	assert.False(t, IsAuthError(err))
+
+	c = newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(401, `{"success":false,"code":401,"message":"Invalid credentials"}`)
+	})
+	_, err = c.GetStatus()
+	assert.True(t, IsAuthError(err))
+	assert.True(t, IsAuthError(fmt.Errorf("sync: %w", err)))
+	assert.False(t, IsAuthError(errors.New("Invalid credentials")))
+	assert.False(t, IsAuthError(nil))
+}
+
+func TestAPIErrorNonJSONBody(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		res := jsonResponse(503, "<html><body><h1>503 Service Temporarily Unavailable</h1></body></html>\n")
+		res.Status = "503 Service Unavailable"
+		res.Header.Set("Content-Type", "text/html")
+		return res
+	})
+	_, err := c.GetStatus()
+	var e *APIError
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, 503, e.StatusCode)
+		assert.Equal(t, 503, e.Code)
+		assert.Equal(t, "<html><body><h1>503 Service Temporarily Unavailable</h1></body></html>", e.Body)
+		assert.EqualError(t, err, "503 Service Unavailable: <html><body><h1>503 Service Temporarily Unavailable</h1></body></html>")
+	}
+
+	c = newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(500, "")
+	})
+	_, err = c.GetStatus()
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, 500, e.StatusCode)
+		assert.Empty(t, e.Body)
+		assert.EqualError(t, err, "500 Internal Server Error")
+	}
+}
+
+func TestErrStorageFull(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/syncdata" {
+			return jsonResponse(507, `{"success":false,"code":507,"message":"Insufficient storage"}`)
+		}
+		return jsonResponse(400, `{"success":false,"code":400,"message":"Storage quota exceeded for this account"}`)
+	})
+	_, err := c.FirstSyncData()
+	assert.True(t, errors.Is(err, ErrStorageFull))
+	_, err = c.GetStatus()
+	assert.True(t, errors.Is(err, ErrStorageFull))
+
+	assert.False(t, errors.Is(&APIError{StatusCode: 400, Code: 400, Message: "Invalid transaction"}, ErrStorageFull))
+}
+
+func TestUnsuccessfulResponse(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/getstatus" {
+			return jsonResponse(200, `{"success":false,"code":401,"message":"Invalid credentials"}`)
+		}
+		if req.URL.Query().Get("lastTransactionId") == "old" {
+			return jsonResponse(200, `{"success":false,"code":400,"message":"Invalid transaction"}`)
+		}
+		return jsonResponse(200, `{"success":false}`)
+	})
+	s, err := c.GetStatus()
+	assert.Nil(t, s)
+	var e *APIError
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, &APIError{StatusCode: 200, Code: 401, Message: "Invalid credentials"}, e)
+	}
+	assert.True(t, IsAuthError(err))
+
+	_, err = c.FirstSyncData()
+	assert.EqualError(t, err, "request unsuccessful")
+	_, err = c.SyncData("old", true)
+	assert.True(t, errors.Is(err, ErrTransactionExpired))
+}
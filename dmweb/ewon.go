@@ -0,0 +1,50 @@
+package dmweb
+
+import (
+	"math"
+	"time"
+)
+
+// TagByName returns the first tag of the eWON with the given name.
+func (e *Ewon) TagByName(name string) (*Tag, bool) {
+	for _, t := range e.Tags {
+		if t != nil && t.Name == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// TagByEwonTagID returns the tag of the eWON with the given ewonTagId,
+// the ID of the tag on the eWON itself, which, unlike the name, is
+// stable across renames.
+func (e *Ewon) TagByEwonTagID(id int) (*Tag, bool) {
+	for _, t := range e.Tags {
+		if t != nil && t.EwonTagID == id {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// StaleSince returns how long before now the eWON last uploaded data to
+// the DataMailbox, according to LastSynchroDate. For an eWON that never
+// uploaded data, it returns the maximum duration.
+func (e *Ewon) StaleSince(now time.Time) time.Duration {
+	if e.LastSynchroDate.IsZero() {
+		return time.Duration(math.MaxInt64)
+	}
+	return now.Sub(e.LastSynchroDate)
+}
+
+// Stale returns the eWONs that last uploaded data more than threshold
+// before now, see Ewon.StaleSince, in order.
+func (es Ewons) Stale(threshold time.Duration, now time.Time) Ewons {
+	var out Ewons
+	for _, e := range es {
+		if e != nil && e.StaleSince(now) > threshold {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,46 @@
+package dmweb
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEwonTagLookups(t *testing.T) {
+	e := &Ewon{Tags: Tags{
+		{ID: 1, Name: "A", EwonTagID: 10},
+		nil,
+		{ID: 2, Name: "B", EwonTagID: 20},
+	}}
+
+	tag, ok := e.TagByName("B")
+	assert.True(t, ok)
+	assert.Equal(t, 2, tag.ID)
+	tag.Value = 3
+	assert.Equal(t, 3.0, e.Tags[2].Value)
+
+	tag, ok = e.TagByEwonTagID(10)
+	assert.True(t, ok)
+	assert.Same(t, e.Tags[0], tag)
+
+	_, ok = e.TagByName("C")
+	assert.False(t, ok)
+	_, ok = e.TagByEwonTagID(30)
+	assert.False(t, ok)
+}
+
+func TestStale(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	es := Ewons{
+		{ID: 1, LastSynchroDate: now.Add(-time.Minute)},
+		{ID: 2, LastSynchroDate: now.Add(-2 * time.Hour)},
+		nil,
+		{ID: 3},
+	}
+	assert.Equal(t, time.Minute, es[0].StaleSince(now))
+	assert.Equal(t, time.Duration(math.MaxInt64), es[3].StaleSince(now))
+	assert.Equal(t, Ewons{es[1], es[3]}, es.Stale(time.Hour, now))
+	assert.Equal(t, Ewons{es[3]}, es.Stale(time.Hour, now.Add(-2*time.Hour)))
+}
@@ -0,0 +1,62 @@
+package dmweb
+
+// ewonFilter selects eWONs by ID, see WithEwonFilter.
+type ewonFilter struct {
+	include map[int]bool
+	exclude map[int]bool
+}
+
+func newEwonFilter(include, exclude []int) *ewonFilter {
+	f := &ewonFilter{}
+	if len(include) > 0 {
+		f.include = make(map[int]bool, len(include))
+		for _, id := range include {
+			f.include[id] = true
+		}
+	}
+	if len(exclude) > 0 {
+		f.exclude = make(map[int]bool, len(exclude))
+		for _, id := range exclude {
+			f.exclude[id] = true
+		}
+	}
+	return f
+}
+
+// allows reports whether the eWON with the given ID passes the filter.
+func (f *ewonFilter) allows(id int) bool {
+	if f.exclude[id] {
+		return false
+	}
+	return f.include == nil || f.include[id]
+}
+
+// filterEwons drops the eWONs the filter of the client doesn't allow
+// from es, in place.
+func (c *Client) filterEwons(es Ewons) Ewons {
+	if c.ewonFilter == nil {
+		return es
+	}
+	out := es[:0]
+	for _, e := range es {
+		if e != nil && c.ewonFilter.allows(e.ID) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// filterDataEwons is like filterEwons, for the eWONs of getdata and
+// syncdata responses.
+func (c *Client) filterDataEwons(es []DataEwon) []DataEwon {
+	if c.ewonFilter == nil {
+		return es
+	}
+	out := es[:0]
+	for _, e := range es {
+		if c.ewonFilter.allows(e.ID) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
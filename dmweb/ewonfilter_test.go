@@ -0,0 +1,46 @@
+package dmweb
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithEwonFilter(t *testing.T) {
+	body := `{"success":true,"transactionId":"1","ewons":[{"id":1,"name":"a"},{"id":2,"name":"b"},{"id":3,"name":"c"}]}`
+	client := func(include, exclude []int) *Client {
+		c, err := New(NewTestClient(func(req *http.Request) *http.Response {
+			return jsonResponse(200, body)
+		}), "aid", "username", "password", "devid", WithEwonFilter(include, exclude))
+		assert.NoError(t, err)
+		return c
+	}
+
+	c := client([]int{1, 3}, nil)
+	es, err := c.GetEwons()
+	assert.NoError(t, err)
+	if assert.Len(t, es, 2) {
+		assert.Equal(t, 1, es[0].ID)
+		assert.Equal(t, 3, es[1].ID)
+	}
+
+	c = client([]int{1, 3}, []int{3})
+	d, err := c.GetDataWithParams(GetDataParams{})
+	assert.NoError(t, err)
+	if assert.Len(t, d.Ewons, 1) {
+		assert.Equal(t, 1, d.Ewons[0].ID)
+	}
+
+	c = client(nil, []int{2})
+	s, err := c.FirstSyncData()
+	assert.NoError(t, err)
+	assert.Equal(t, "1", s.TransactionID)
+	if assert.Len(t, s.Ewons, 2) {
+		assert.Equal(t, 1, s.Ewons[0].ID)
+		assert.Equal(t, 3, s.Ewons[1].ID)
+	}
+
+	_, err = New(nil, "aid", "username", "password", "devid", WithEwonFilter(nil, nil))
+	assert.Error(t, err)
+}
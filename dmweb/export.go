@@ -0,0 +1,244 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	errorPagingStalled = errors.New("getdata paging stalled: more data available but timestamp did not advance")
+	errorStopPaging    = errors.New("stop paging")
+)
+
+// Record is a single history point flattened together with the
+// eWON and tag it belongs to.
+type Record struct {
+	EwonID   int       `json:"ewonId"`
+	EwonName string    `json:"ewonName"`
+	TagID    int       `json:"tagId"`
+	TagName  string    `json:"tagName"`
+	DataType DataType  `json:"dataType,omitempty"`
+	Date     time.Time `json:"date"`
+	Value    float64   `json:"value"`
+	Quality  Quality   `json:"quality,omitempty"`
+	// serverDate is the date as returned by the DataMailbox, see
+	// HistoryPoint.
+	serverDate time.Time
+}
+
+// rawDate is like HistoryPoint.rawDate.
+func (r Record) rawDate() time.Time {
+	if !r.serverDate.IsZero() {
+		return r.serverDate
+	}
+	return r.Date
+}
+
+// recordKey identifies a history point across responses. The date is
+// the one returned by the DataMailbox, in UTC, as localized dates of
+// different responses don't share their *time.Location and so don't
+// compare equal as map keys.
+type recordKey struct {
+	ewonID int
+	tagID  int
+	date   time.Time
+}
+
+func (r Record) key() recordKey {
+	return recordKey{r.EwonID, r.TagID, r.rawDate().UTC()}
+}
+
+// records flattens the history of every tag of every eWON, in order.
+func records(ewons []DataEwon) []Record {
+	var rs []Record
+	for _, e := range ewons {
+		for _, t := range e.Tags {
+			for _, h := range t.History {
+				rs = append(rs, Record{
+					EwonID:     e.ID,
+					EwonName:   e.Name,
+					TagID:      t.ID,
+					TagName:    t.Name,
+					DataType:   t.DataType,
+					Date:       h.Date,
+					Value:      h.Value,
+					Quality:    h.Quality,
+					serverDate: h.serverDate,
+				})
+			}
+		}
+	}
+	return rs
+}
+
+// Records flattens the history of every tag of every eWON in the
+// response, in response order.
+func (d *GetDataResponse) Records() []Record {
+	return records(d.Ewons)
+}
+
+// Records flattens the history of every tag of every eWON in the
+// response, in response order.
+func (s *SyncResponse) Records() []Record {
+	return records(s.Ewons)
+}
+
+// export pages getdata from from to to, following moreDataAvailable
+// with the MoreDataID of the page. When the DataMailbox doesn't return
+// one, the next page is requested by advancing from to the newest
+// timestamp received instead; because from is inclusive, points on that
+// boundary are returned twice and are dropped from the second page. An
+// ewonID of 0 exports all eWONs at once, and a zero from or to leaves
+// the range open on that side.
+func (c *Client) export(ctx context.Context, ewonID int, from, to time.Time, fn func([]Record) error) error {
+	var params GetDataParams
+	if !from.IsZero() {
+		params.From = &from
+	}
+	if !to.IsZero() {
+		params.To = &to
+	}
+	if ewonID != 0 {
+		params.EwonID = &ewonID
+	}
+	var boundary map[recordKey]bool
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		d, err := c.GetDataWithParamsContext(ctx, params)
+		if err != nil {
+			return err
+		}
+		rs := d.Records()
+		newest := from
+		page := make([]Record, 0, len(rs))
+		for _, r := range rs {
+			if r.rawDate().After(newest) {
+				newest = r.rawDate()
+			}
+			if boundary[r.key()] {
+				continue
+			}
+			page = append(page, r)
+		}
+		if len(page) > 0 {
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+		if !d.MoreDataAvailable {
+			return nil
+		}
+		if d.MoreDataID != "" {
+			params.MoreData = string(d.MoreDataID)
+			boundary = nil
+			continue
+		}
+		if !newest.After(from) {
+			return errorPagingStalled
+		}
+		from = newest
+		params.From = &from
+		params.MoreData = ""
+		boundary = make(map[recordKey]bool)
+		for _, r := range rs {
+			if r.rawDate().Equal(newest) {
+				boundary[r.key()] = true
+			}
+		}
+	}
+}
+
+// ExportAll exports the history between from and to of every eWON in
+// the account, paging getdata for all eWONs at once.
+// When maxPoints is positive, paging stops as soon as maxPoints points
+// have been delivered to fn. Since the DataMailbox returns the oldest
+// data first, these are the oldest maxPoints points of the range; the
+// page that reaches the cap is sorted by date and cut at exactly
+// maxPoints. A maxPoints of 0 exports the whole range.
+func (c *Client) ExportAll(ctx context.Context, from, to time.Time, maxPoints int, fn func([]Record) error) error {
+	delivered := 0
+	err := c.export(ctx, 0, from, to, func(rs []Record) error {
+		if maxPoints <= 0 {
+			return fn(rs)
+		}
+		last := false
+		if left := maxPoints - delivered; len(rs) >= left {
+			sort.SliceStable(rs, func(i, j int) bool { return rs[i].Date.Before(rs[j].Date) })
+			rs = rs[:left]
+			last = true
+		}
+		delivered += len(rs)
+		if err := fn(rs); err != nil {
+			return err
+		}
+		if last {
+			return errorStopPaging
+		}
+		return nil
+	})
+	if err == errorStopPaging {
+		return nil
+	}
+	return err
+}
+
+// ExportAllConcurrent exports the history between from and to of every
+// eWON in the account. The eWONs are listed with GetEwons and each one
+// is paged with getdata independently, with at most ewonConcurrency
+// eWONs in flight at once.
+// Calls to fn are serialized, so fn does not need to be safe for
+// concurrent use, but pages of different eWONs arrive interleaved.
+// A failing eWON, including fn returning an error for one of its pages,
+// does not stop the others; the failures are returned as EwonErrors.
+func (c *Client) ExportAllConcurrent(ctx context.Context, from, to time.Time, ewonConcurrency int, fn func([]Record) error) error {
+	if ewonConcurrency < 1 {
+		ewonConcurrency = 1
+	}
+	es, err := c.GetEwonsContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		fnMu  sync.Mutex
+		errMu sync.Mutex
+		wg    sync.WaitGroup
+	)
+	errs := EwonErrors{}
+	serialized := func(rs []Record) error {
+		fnMu.Lock()
+		defer fnMu.Unlock()
+		return fn(rs)
+	}
+	sem := make(chan struct{}, ewonConcurrency)
+	for _, e := range es {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errMu.Lock()
+			errs[e.ID] = ctx.Err()
+			errMu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := c.export(ctx, id, from, to, serialized); err != nil {
+				errMu.Lock()
+				errs[id] = err
+				errMu.Unlock()
+			}
+		}(e.ID)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
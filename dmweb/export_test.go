@@ -0,0 +1,189 @@
+package dmweb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func jsonResponse(status int, body string) *http.Response {
+	h := make(http.Header)
+	h.Add("Content-Type", "application/json;charset=UTF-8")
+	return &http.Response{
+		StatusCode: status,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+		Header:     h,
+	}
+}
+
+func newTestDMWebClient(fn roundTripFunc) *Client {
+	return &Client{
+		Client:    NewTestClient(fn),
+		AccountID: "aid",
+		Username:  "username",
+		Password:  "password",
+		DevID:     "devid",
+		baseURL:   DefaultBaseURL,
+		userAgent: DefaultUserAgent,
+	}
+}
+
+func TestExportAllConcurrent(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		waiting int
+	)
+	both := make(chan struct{})
+	parallel := true
+
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		switch req.URL.Path {
+		case "/getewons":
+			return jsonResponse(200, `{"success":true,"ewons":[{"id":1,"name":"Ewon1"},{"id":2,"name":"Ewon2"}]}`)
+		case "/getdata":
+			id := req.URL.Query().Get("ewonId")
+			from := req.URL.Query().Get("from")
+			if from == "2018-11-08T00:00:00Z" {
+				// Hold the first page of each eWON until both are in flight.
+				mu.Lock()
+				waiting++
+				if waiting == 2 {
+					close(both)
+				}
+				mu.Unlock()
+				select {
+				case <-both:
+				case <-time.After(time.Second):
+					mu.Lock()
+					parallel = false
+					mu.Unlock()
+				}
+				return jsonResponse(200, fmt.Sprintf(`{"success":true,"moreDataAvailable":true,"ewons":[{"id":%s,"name":"Ewon%s","tags":[{"id":10,"name":"TAG","history":[
+					{"date":"2018-11-08T14:17:58Z","value":1},
+					{"date":"2018-11-08T14:18:00Z","value":2}]}]}]}`, id, id))
+			}
+			assert.Equal(t, "2018-11-08T14:18:00Z", from)
+			return jsonResponse(200, fmt.Sprintf(`{"success":true,"moreDataAvailable":false,"ewons":[{"id":%s,"name":"Ewon%s","tags":[{"id":10,"name":"TAG","history":[
+				{"date":"2018-11-08T14:18:00Z","value":2},
+				{"date":"2018-11-08T14:18:02Z","value":3}]}]}]}`, id, id))
+		}
+		t.Errorf("unexpected request to %s", req.URL.Path)
+		return jsonResponse(404, `{"success":false,"code":404,"message":"not found"}`)
+	})
+
+	from, _ := time.Parse(time.RFC3339, "2018-11-08T00:00:00Z")
+	to, _ := time.Parse(time.RFC3339, "2018-11-09T00:00:00Z")
+	got := map[int][]float64{}
+	err := c.ExportAllConcurrent(context.Background(), from, to, 2, func(rs []Record) error {
+		for _, r := range rs {
+			got[r.EwonID] = append(got[r.EwonID], r.Value)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, parallel, "eWONs were not paged in parallel")
+	assert.Equal(t, []float64{1, 2, 3}, got[1])
+	assert.Equal(t, []float64{1, 2, 3}, got[2])
+}
+
+func TestExportAllConcurrentErrors(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/getewons" {
+			return jsonResponse(200, `{"success":true,"ewons":[{"id":1,"name":"Ewon1"},{"id":2,"name":"Ewon2"}]}`)
+		}
+		if req.URL.Query().Get("ewonId") == "2" {
+			return jsonResponse(500, `{"success":false,"code":500,"message":"boom"}`)
+		}
+		return jsonResponse(200, `{"success":true,"moreDataAvailable":false,"ewons":[{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"TAG","history":[{"date":"2018-11-08T14:17:58Z","value":1}]}]}]}`)
+	})
+
+	n := 0
+	err := c.ExportAllConcurrent(context.Background(), time.Time{}, time.Now(), 2, func(rs []Record) error {
+		n += len(rs)
+		return nil
+	})
+	assert.Equal(t, 1, n)
+	if assert.IsType(t, EwonErrors{}, err) {
+		errs := err.(EwonErrors)
+		assert.Len(t, errs, 1)
+		assert.EqualError(t, errs[2], "boom")
+	}
+}
+
+func TestExportAllMaxPoints(t *testing.T) {
+	pages := 0
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		pages++
+		assert.Empty(t, req.URL.Query().Get("ewonId"))
+		switch req.URL.Query().Get("from") {
+		case "2018-11-08T00:00:00Z":
+			return jsonResponse(200, `{"success":true,"moreDataAvailable":true,"ewons":[{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"TAG","history":[
+				{"date":"2018-11-08T14:00:00Z","value":1},
+				{"date":"2018-11-08T14:02:00Z","value":3}]}]},{"id":2,"name":"Ewon2","tags":[{"id":20,"name":"TAG","history":[
+				{"date":"2018-11-08T14:01:00Z","value":2}]}]}]}`)
+		case "2018-11-08T14:02:00Z":
+			return jsonResponse(200, `{"success":true,"moreDataAvailable":true,"ewons":[{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"TAG","history":[
+				{"date":"2018-11-08T14:04:00Z","value":5}]}]},{"id":2,"name":"Ewon2","tags":[{"id":20,"name":"TAG","history":[
+				{"date":"2018-11-08T14:03:00Z","value":4}]}]}]}`)
+		}
+		t.Errorf("paging did not stop at the cap: %s", req.URL)
+		return jsonResponse(200, `{"success":true,"moreDataAvailable":false,"ewons":[]}`)
+	})
+
+	from, _ := time.Parse(time.RFC3339, "2018-11-08T00:00:00Z")
+	var got []float64
+	err := c.ExportAll(context.Background(), from, from.Add(24*time.Hour), 4, func(rs []Record) error {
+		for _, r := range rs {
+			got = append(got, r.Value)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pages)
+	assert.ElementsMatch(t, []float64{1, 2, 3, 4}, got)
+}
+
+func TestExportAllMoreDataID(t *testing.T) {
+	var requests []string
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		q := req.URL.Query()
+		requests = append(requests, q.Get("from")+" "+q.Get("moreData"))
+		switch len(requests) {
+		case 1:
+			return jsonResponse(200, `{"success":true,"moreDataAvailable":true,"moreDataId":"m1","ewons":[{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"TAG","history":[
+				{"date":"2018-11-08T10:00:00Z","value":1}]}]}]}`)
+		case 2:
+			// Without a MoreDataID, the next page is requested from the
+			// newest timestamp.
+			return jsonResponse(200, `{"success":true,"moreDataAvailable":true,"ewons":[{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"TAG","history":[
+				{"date":"2018-11-08T10:30:00Z","value":2},
+				{"date":"2018-11-08T11:00:00Z","value":3}]}]}]}`)
+		}
+		return jsonResponse(200, `{"success":true,"moreDataAvailable":false,"ewons":[{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"TAG","history":[
+			{"date":"2018-11-08T11:00:00Z","value":3},
+			{"date":"2018-11-08T12:00:00Z","value":4}]}]}]}`)
+	})
+
+	from, _ := time.Parse(time.RFC3339, "2018-11-08T00:00:00Z")
+	var got []float64
+	err := c.ExportAll(context.Background(), from, time.Time{}, 0, func(rs []Record) error {
+		for _, r := range rs {
+			got = append(got, r.Value)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"2018-11-08T00:00:00Z ",
+		"2018-11-08T00:00:00Z m1",
+		"2018-11-08T11:00:00Z ",
+	}, requests)
+	assert.Equal(t, []float64{1, 2, 3, 4}, got)
+}
@@ -0,0 +1,59 @@
+package dmweb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// reservedParams are the authentication parameters, which extra
+// parameters can't override.
+var reservedParams = map[string]bool{
+	"t2maccount":  true,
+	"t2musername": true,
+	"t2mpassword": true,
+	"t2mtoken":    true,
+	"t2mdevid":    true,
+}
+
+// checkExtraParams returns an error for the first reserved key of v.
+func checkExtraParams(v url.Values) error {
+	for k := range v {
+		if reservedParams[k] {
+			return fmt.Errorf("parameter %s is reserved", k)
+		}
+	}
+	return nil
+}
+
+type extraParamsKey struct{}
+
+// ContextWithExtraParams returns a copy of ctx that makes the requests
+// it controls carry the parameters v, on top of those configured with
+// WithExtraParams, e.g. to pass a flag the DMWeb API introduced before
+// this package models it to a single call. Requests with a reserved
+// authentication parameter in v fail.
+func ContextWithExtraParams(ctx context.Context, v url.Values) context.Context {
+	return context.WithValue(ctx, extraParamsKey{}, v)
+}
+
+// mergeExtraParams returns params on top of the default parameters of
+// endpoint, with the extra parameters of the client and then of ctx set,
+// replacing parameters of the same name. params itself is not modified.
+func (c *Client) mergeExtraParams(ctx context.Context, endpoint string, params url.Values) (url.Values, error) {
+	extra, _ := ctx.Value(extraParamsKey{}).(url.Values)
+	defaults := c.defaultParams[endpoint]
+	if len(defaults) == 0 && len(c.extraParams) == 0 && len(extra) == 0 {
+		return params, nil
+	}
+	if err := checkExtraParams(extra); err != nil {
+		return nil, err
+	}
+	out := make(url.Values, len(defaults)+len(params)+len(c.extraParams)+len(extra))
+	for _, e := range []url.Values{defaults, params, c.extraParams, extra} {
+		for k, vs := range e {
+			out[k] = vs
+		}
+	}
+	return out, nil
+}
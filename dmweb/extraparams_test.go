@@ -0,0 +1,73 @@
+package dmweb
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtraParams(t *testing.T) {
+	var queries []url.Values
+	c, err := New(NewTestClient(func(req *http.Request) *http.Response {
+		queries = append(queries, req.URL.Query())
+		return jsonResponse(200, `{"success":true,"transactionId":"1","ewons":[]}`)
+	}), "aid", "username", "password", "devid",
+		WithExtraParams(url.Values{"newFlag": {"1"}, "createTransaction": {"false"}}))
+	assert.NoError(t, err)
+
+	_, err = c.FirstSyncData()
+	assert.NoError(t, err)
+	ctx := ContextWithExtraParams(context.Background(), url.Values{"newFlag": {"2"}, "other": {"x"}})
+	_, err = c.FirstSyncDataContext(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, queries, 2) {
+		assert.Equal(t, "1", queries[0].Get("newFlag"))
+		assert.Equal(t, "false", queries[0].Get("createTransaction"))
+		assert.Equal(t, "password", queries[0].Get("t2mpassword"))
+		assert.Equal(t, "2", queries[1].Get("newFlag"))
+		assert.Equal(t, "x", queries[1].Get("other"))
+	}
+
+	ctx = ContextWithExtraParams(context.Background(), url.Values{"t2mpassword": {"x"}})
+	_, err = c.FirstSyncDataContext(ctx)
+	assert.EqualError(t, err, "parameter t2mpassword is reserved")
+	assert.Len(t, queries, 2)
+
+	_, err = New(nil, "aid", "username", "password", "devid", WithExtraParams(url.Values{"t2mdevid": {"x"}}))
+	assert.EqualError(t, err, "parameter t2mdevid is reserved")
+}
+
+func TestDefaultParams(t *testing.T) {
+	var queries []url.Values
+	c, err := New(NewTestClient(func(req *http.Request) *http.Response {
+		queries = append(queries, req.URL.Query())
+		return jsonResponse(200, `{"success":true,"ewons":[]}`)
+	}), "aid", "username", "password", "devid",
+		WithDefaultParams(EndpointGetData, url.Values{"fullConfig": {""}, "limit": {"500"}}))
+	assert.NoError(t, err)
+
+	_, err = c.GetDataWithParams(GetDataParams{})
+	assert.NoError(t, err)
+	limit := 10
+	_, err = c.GetDataWithParams(GetDataParams{Limit: &limit})
+	assert.NoError(t, err)
+	_, err = c.GetEwons()
+	assert.NoError(t, err)
+	_, err = c.Request(context.Background(), EndpointGetEwons, url.Values{"t2mpassword": {"x"}})
+	assert.NoError(t, err)
+	if assert.Len(t, queries, 4) {
+		_, ok := queries[0]["fullConfig"]
+		assert.True(t, ok)
+		assert.Equal(t, "500", queries[0].Get("limit"))
+		assert.Equal(t, "10", queries[1].Get("limit"))
+		assert.Empty(t, queries[2].Get("limit"))
+		assert.Equal(t, []string{"password"}, queries[3]["t2mpassword"])
+	}
+
+	_, err = New(nil, "aid", "username", "password", "devid",
+		WithDefaultParams(EndpointGetData, url.Values{"t2maccount": {"x"}}))
+	assert.EqualError(t, err, "parameter t2maccount is reserved")
+}
@@ -0,0 +1,65 @@
+package dmweb
+
+import "strings"
+
+// tagsByName returns the tags of ewons named one of names, in response
+// order, comparing names case-insensitively if fold is set. The returned
+// tags point into ewons.
+func tagsByName(ewons []DataEwon, names []string, fold bool) []*DataTag {
+	want := make(map[string]bool, len(names))
+	for _, n := range names {
+		if fold {
+			n = strings.ToLower(n)
+		}
+		want[n] = true
+	}
+	ts := []*DataTag{}
+	for i := range ewons {
+		for j := range ewons[i].Tags {
+			t := &ewons[i].Tags[j]
+			n := t.Name
+			if fold {
+				n = strings.ToLower(n)
+			}
+			if want[n] {
+				ts = append(ts, t)
+			}
+		}
+	}
+	return ts
+}
+
+// TagsByName returns the tags of the eWON named one of names, with their
+// history. It returns an empty slice when no tag matches.
+func (e *DataEwon) TagsByName(names ...string) []*DataTag {
+	return tagsByName([]DataEwon{*e}, names, false)
+}
+
+// TagsByNameFold is like TagsByName, comparing names case-insensitively.
+func (e *DataEwon) TagsByNameFold(names ...string) []*DataTag {
+	return tagsByName([]DataEwon{*e}, names, true)
+}
+
+// TagsByName returns the tags of all eWONs in the response named one of
+// names, with their history, in response order. It returns an empty
+// slice when no tag matches.
+func (s *SyncResponse) TagsByName(names ...string) []*DataTag {
+	return tagsByName(s.Ewons, names, false)
+}
+
+// TagsByNameFold is like TagsByName, comparing names case-insensitively.
+func (s *SyncResponse) TagsByNameFold(names ...string) []*DataTag {
+	return tagsByName(s.Ewons, names, true)
+}
+
+// TagsByName returns the tags of all eWONs in the response named one of
+// names, with their history, in response order. It returns an empty
+// slice when no tag matches.
+func (d *GetDataResponse) TagsByName(names ...string) []*DataTag {
+	return tagsByName(d.Ewons, names, false)
+}
+
+// TagsByNameFold is like TagsByName, comparing names case-insensitively.
+func (d *GetDataResponse) TagsByNameFold(names ...string) []*DataTag {
+	return tagsByName(d.Ewons, names, true)
+}
@@ -0,0 +1,38 @@
+package dmweb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagsByName(t *testing.T) {
+	s := &SyncResponse{Ewons: []DataEwon{
+		{ID: 1, Tags: []DataTag{{ID: 1, Name: "Temp"}, {ID: 2, Name: "Pressure"}}},
+		{ID: 2, Tags: []DataTag{{ID: 3, Name: "temp"}, {ID: 4, Name: "Flow"}}},
+	}}
+
+	ts := s.TagsByName("Temp", "Flow")
+	if assert.Len(t, ts, 2) {
+		assert.Equal(t, 1, ts[0].ID)
+		assert.Equal(t, 4, ts[1].ID)
+	}
+	ts[0].Value = 42
+	assert.Equal(t, 42.0, s.Ewons[0].Tags[0].Value)
+
+	ts = s.TagsByNameFold("TEMP")
+	if assert.Len(t, ts, 2) {
+		assert.Equal(t, 3, ts[1].ID)
+	}
+
+	assert.NotNil(t, s.TagsByName("Level"))
+	assert.Empty(t, s.TagsByName("Level"))
+
+	ts = s.Ewons[1].TagsByName("temp")
+	if assert.Len(t, ts, 1) {
+		assert.Equal(t, 3, ts[0].ID)
+	}
+
+	d := &GetDataResponse{Ewons: s.Ewons}
+	assert.Len(t, d.TagsByNameFold("pressure"), 1)
+}
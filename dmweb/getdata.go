@@ -0,0 +1,181 @@
+package dmweb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// DefaultMaxDataPages is the maximum number of getdata requests made by
+// a single GetDataAll call.
+const DefaultMaxDataPages = 1000
+
+// ErrMaxDataPagesExceeded is returned by GetDataAll when the DataMailbox
+// still reports more data available after DefaultMaxDataPages requests.
+var ErrMaxDataPagesExceeded = errors.New("more data available after the maximum number of getdata pages")
+
+// GetDataAll is like GetDataWithParamsContext, but follows
+// moreDataAvailable until the DataMailbox reports no more data
+// available. The next page is requested with the MoreDataID of the
+// previous one, or, when the DataMailbox didn't return one, from the
+// newest timestamp received, in which case points on the page
+// boundaries, which the DataMailbox returns on both pages, are kept
+// once. The pages are merged into a single response, with the history
+// of every tag in order.
+// At most DefaultMaxDataPages requests are made. On error, the data
+// received so far is returned along with it.
+func (c *Client) GetDataAll(ctx context.Context, params GetDataParams) (*GetDataResponse, error) {
+	out := &GetDataResponse{Success: true}
+	m := newDataMerger(out)
+	ps, err := params.perTag()
+	if err != nil {
+		return nil, err
+	}
+	if ps != nil {
+		// Every tag is paged on its own, as its pages end at different
+		// timestamps.
+		for _, p := range ps {
+			d, err := c.GetDataAll(ctx, p)
+			m.add(d.Ewons, nil)
+			out.Meta = d.Meta
+			if err != nil {
+				out.MoreDataAvailable = d.MoreDataAvailable
+				return out, err
+			}
+		}
+		return out, nil
+	}
+	var boundary map[recordKey]bool
+	for i := 0; i < DefaultMaxDataPages; i++ {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+		d, err := c.GetDataWithParamsContext(ctx, params)
+		if err != nil {
+			return out, err
+		}
+		out.Meta = d.Meta
+		newest := m.add(d.Ewons, boundary)
+		if !d.MoreDataAvailable {
+			return out, nil
+		}
+		if d.MoreDataID != "" {
+			params.MoreData = string(d.MoreDataID)
+			boundary = nil
+			continue
+		}
+		if params.From != nil && !newest.After(*params.From) || newest.IsZero() {
+			return out, errorPagingStalled
+		}
+		from := newest
+		params.From = &from
+		boundary = make(map[recordKey]bool)
+		for _, r := range records(d.Ewons) {
+			if r.rawDate().Equal(newest) {
+				boundary[r.key()] = true
+			}
+		}
+	}
+	out.MoreDataAvailable = true
+	return out, ErrMaxDataPagesExceeded
+}
+
+// GetDataPage gets a page of at most limit historical values matching
+// params, and whether more data is available. When more data matches
+// than limit allows, the DataMailbox returns the oldest values. To get
+// the next page, set params.From to the newest timestamp of the page,
+// whose values are then returned again, or params.MoreData to the
+// MoreDataID of the page when the DataMailbox returned one. GetDataAll
+// does this paging.
+func (c *Client) GetDataPage(params GetDataParams, limit int) (*GetDataResponse, bool, error) {
+	return c.GetDataPageContext(context.Background(), params, limit)
+}
+
+// GetDataPageContext is like GetDataPage, with ctx controlling the
+// request.
+func (c *Client) GetDataPageContext(ctx context.Context, params GetDataParams, limit int) (*GetDataResponse, bool, error) {
+	if limit <= 0 {
+		return nil, false, errors.New("limit must be positive")
+	}
+	params.Limit = &limit
+	d, err := c.GetDataWithParamsContext(ctx, params)
+	if err != nil {
+		return d, false, err
+	}
+	return d, d.MoreDataAvailable, nil
+}
+
+// dataMerger merges the eWONs of getdata pages into a response, by eWON
+// and tag ID.
+type dataMerger struct {
+	out   *GetDataResponse
+	ewons map[int]int
+	tags  map[tagKey]int
+}
+
+func newDataMerger(out *GetDataResponse) *dataMerger {
+	return &dataMerger{out: out, ewons: make(map[int]int), tags: make(map[tagKey]int)}
+}
+
+// add merges ewons into the response, skipping the history points in
+// skip, and returns the newest timestamp of ewons as returned by the
+// DataMailbox.
+func (m *dataMerger) add(ewons []DataEwon, skip map[recordKey]bool) time.Time {
+	var newest time.Time
+	for _, e := range ewons {
+		ei, ok := m.ewons[e.ID]
+		if !ok {
+			ei = len(m.out.Ewons)
+			m.ewons[e.ID] = ei
+			ne := e
+			ne.Tags = nil
+			m.out.Ewons = append(m.out.Ewons, ne)
+		}
+		oe := &m.out.Ewons[ei]
+		if e.LastSynchroDate.After(oe.LastSynchroDate) {
+			oe.LastSynchroDate = e.LastSynchroDate
+		}
+		for _, t := range e.Tags {
+			k := tagKey{ewonID: e.ID, tagID: t.ID}
+			ti, ok := m.tags[k]
+			if !ok {
+				ti = len(oe.Tags)
+				m.tags[k] = ti
+				nt := t
+				nt.History = nil
+				oe.Tags = append(oe.Tags, nt)
+			}
+			ot := &oe.Tags[ti]
+			for _, h := range t.History {
+				if h.rawDate().After(newest) {
+					newest = h.rawDate()
+				}
+				if skip[recordKey{ewonID: e.ID, tagID: t.ID, date: h.rawDate().UTC()}] {
+					continue
+				}
+				ot.History = append(ot.History, h)
+			}
+		}
+	}
+	return newest
+}
+
+// MoreDataID is the continuation of a getdata response with more data
+// available. The DataMailbox returns it as a string or a number.
+type MoreDataID string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *MoreDataID) UnmarshalJSON(b []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err == nil {
+		*m = MoreDataID(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*m = MoreDataID(s)
+	return nil
+}
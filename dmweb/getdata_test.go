@@ -0,0 +1,159 @@
+package dmweb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDataAll(t *testing.T) {
+	var froms []string
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		from := req.URL.Query().Get("from")
+		froms = append(froms, from)
+		assert.Equal(t, "1", req.URL.Query().Get("ewonId"))
+		switch from {
+		case "2020-01-01T00:00:00Z":
+			return jsonResponse(200, `{"success":true,"moreDataAvailable":true,"ewons":[{"id":1,"name":"Ewon1","tags":[
+				{"id":10,"name":"A","history":[{"date":"2020-01-01T00:00:00Z","value":1},{"date":"2020-01-01T00:01:00Z","value":2}]},
+				{"id":11,"name":"B","history":[{"date":"2020-01-01T00:01:00Z","value":3}]}]}]}`)
+		case "2020-01-01T00:01:00Z":
+			return jsonResponse(200, `{"success":true,"moreDataAvailable":false,"ewons":[{"id":1,"name":"Ewon1","tags":[
+				{"id":11,"name":"B","history":[{"date":"2020-01-01T00:01:00Z","value":3},{"date":"2020-01-01T00:02:00Z","value":4}]},
+				{"id":12,"name":"C","history":[{"date":"2020-01-01T00:02:00Z","value":5}]}]}]}`)
+		}
+		t.Fatalf("unexpected from %q", from)
+		return nil
+	})
+
+	ewonID := 1
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := c.GetDataAll(context.Background(), GetDataParams{EwonID: &ewonID, From: &from})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2020-01-01T00:00:00Z", "2020-01-01T00:01:00Z"}, froms)
+	assert.False(t, d.MoreDataAvailable)
+	if assert.Len(t, d.Ewons, 1) && assert.Len(t, d.Ewons[0].Tags, 3) {
+		values := func(h []HistoryPoint) []float64 {
+			var vs []float64
+			for _, p := range h {
+				vs = append(vs, p.Value)
+			}
+			return vs
+		}
+		tags := d.Ewons[0].Tags
+		assert.Equal(t, []float64{1, 2}, values(tags[0].History))
+		assert.Equal(t, []float64{3, 4}, values(tags[1].History))
+		assert.Equal(t, []float64{5}, values(tags[2].History))
+	}
+}
+
+func TestGetDataAllStalled(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(200, `{"success":true,"moreDataAvailable":true,"ewons":[]}`)
+	})
+	d, err := c.GetDataAll(context.Background(), GetDataParams{})
+	assert.Equal(t, errorPagingStalled, err)
+	assert.NotNil(t, d)
+}
+
+func TestGetDataTagIDs(t *testing.T) {
+	var tagIDs []string
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		id := req.URL.Query().Get("tagId")
+		tagIDs = append(tagIDs, id)
+		return jsonResponse(200, `{"success":true,"moreDataAvailable":false,"ewons":[{"id":1,"name":"Ewon1","tags":[
+			{"id":`+id+`,"name":"TAG`+id+`","history":[{"date":"2020-01-01T00:00:00Z","value":`+id+`}]}]}]}`)
+	})
+
+	ewonID := 1
+	d, err := c.GetDataWithParams(GetDataParams{EwonID: &ewonID, TagIDs: []int{10, 11, 12}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10", "11", "12"}, tagIDs)
+	if assert.Len(t, d.Ewons, 1) && assert.Len(t, d.Ewons[0].Tags, 3) {
+		assert.Equal(t, "TAG12", d.Ewons[0].Tags[2].Name)
+	}
+
+	tagIDs = nil
+	d, err = c.GetDataAll(context.Background(), GetDataParams{TagIDs: []int{10, 11}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10", "11"}, tagIDs)
+	assert.Len(t, d.Records(), 2)
+
+	tagIDs = nil
+	_, err = c.GetDataWithParams(GetDataParams{TagIDs: []int{10}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10"}, tagIDs)
+
+	tagID := 10
+	_, err = c.GetDataWithParams(GetDataParams{TagID: &tagID, TagIDs: []int{11}})
+	assert.EqualError(t, err, "TagID and TagIDs can't be combined")
+	_, err = c.GetDataCSV(GetDataParams{TagIDs: []int{10, 11}})
+	assert.Equal(t, errorMultipleTagIDs, err)
+}
+
+func TestGetDataTagIDsMoreData(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		id := req.URL.Query().Get("tagId")
+		more := `"moreDataAvailable":false`
+		if id == "11" {
+			more = `"moreDataAvailable":true,"moreDataId":"m11"`
+		}
+		return jsonResponse(200, `{"success":true,`+more+`,"ewons":[{"id":1,"name":"Ewon1","tags":[
+			{"id":`+id+`,"name":"TAG`+id+`","history":[{"date":"2020-01-01T00:00:00Z","value":`+id+`}]}]}]}`)
+	})
+
+	d, err := c.GetDataWithParams(GetDataParams{TagIDs: []int{10, 11, 12}})
+	assert.NoError(t, err)
+	assert.True(t, d.MoreDataAvailable)
+	assert.Empty(t, d.MoreDataID)
+	assert.Equal(t, map[int]MoreDataID{11: "m11"}, d.MoreDataIDs)
+
+	_, err = c.GetDataWithParams(GetDataParams{TagIDs: []int{10, 11}, MoreData: "m11"})
+	assert.EqualError(t, err, "MoreData can't be combined with several TagIDs")
+}
+
+func TestGetDataAllMoreDataID(t *testing.T) {
+	var moreData []string
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		q := req.URL.Query()
+		assert.Equal(t, "2020-01-01T00:00:00Z", q.Get("from"))
+		moreData = append(moreData, q.Get("moreData"))
+		switch q.Get("moreData") {
+		case "":
+			return jsonResponse(200, `{"success":true,"moreDataAvailable":true,"moreDataId":42,"ewons":[{"id":1,"name":"Ewon1","tags":[
+				{"id":10,"name":"A","history":[{"date":"2020-01-01T00:00:00Z","value":1},{"date":"2020-01-01T00:01:00Z","value":2}]}]}]}`)
+		case "42":
+			return jsonResponse(200, `{"success":true,"moreDataAvailable":true,"moreDataId":"43","ewons":[{"id":1,"name":"Ewon1","tags":[
+				{"id":10,"name":"A","history":[{"date":"2020-01-01T00:01:00Z","value":3}]}]}]}`)
+		}
+		return jsonResponse(200, `{"success":true,"moreDataAvailable":false,"ewons":[]}`)
+	})
+
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := c.GetDataAll(context.Background(), GetDataParams{From: &from})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "42", "43"}, moreData)
+	if assert.Len(t, d.Ewons, 1) && assert.Len(t, d.Ewons[0].Tags, 1) {
+		// Points at the same timestamp aren't duplicates with a
+		// continuation ID.
+		assert.Len(t, d.Ewons[0].Tags[0].History, 3)
+	}
+}
+
+func TestGetDataPage(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "2", req.URL.Query().Get("limit"))
+		return jsonResponse(200, `{"success":true,"moreDataAvailable":true,"ewons":[{"id":1,"name":"Ewon1","tags":[
+			{"id":10,"name":"A","history":[{"date":"2020-01-01T00:00:00Z","value":1},{"date":"2020-01-01T00:01:00Z","value":2}]}]}]}`)
+	})
+	d, more, err := c.GetDataPage(GetDataParams{}, 2)
+	assert.NoError(t, err)
+	assert.True(t, more)
+	assert.Len(t, d.Ewons, 1)
+
+	_, _, err = c.GetDataPage(GetDataParams{}, 0)
+	assert.EqualError(t, err, "limit must be positive")
+}
@@ -0,0 +1,43 @@
+package dmweb
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// gzipBody decompresses a gzipped response body. The gzip reader is
+// created on the first Read, so that an empty body only fails when read.
+type gzipBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.zr == nil && b.err == nil {
+		b.zr, b.err = gzip.NewReader(b.body)
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.zr.Read(p)
+}
+
+func (b *gzipBody) Close() error {
+	return b.body.Close()
+}
+
+// decompress makes res transparently decompress a gzipped body, as the
+// HTTP transport only does so itself when it asked for gzip.
+func decompress(res *http.Response) {
+	if !strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		return
+	}
+	res.Body = &gzipBody{body: res.Body}
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("Content-Length")
+	res.ContentLength = -1
+	res.Uncompressed = true
+}
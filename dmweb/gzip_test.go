@@ -0,0 +1,57 @@
+package dmweb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func gzipBytes(b []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(b)
+	zw.Close()
+	return buf.Bytes()
+}
+
+func gzipResponse(status int, body []byte) *http.Response {
+	res := jsonResponse(status, "")
+	res.Body = ioutil.NopCloser(bytes.NewReader(gzipBytes(body)))
+	res.Header.Set("Content-Encoding", "gzip")
+	return res
+}
+
+func TestCompression(t *testing.T) {
+	payload := largeSyncPayload(10000)
+	compressed := gzipBytes(payload)
+	t.Logf("syncdata payload of %d bytes compresses to %d bytes", len(payload), len(compressed))
+	assert.True(t, len(compressed)*10 < len(payload))
+
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "gzip", req.Header.Get("Accept-Encoding"))
+		return gzipResponse(200, payload)
+	})
+	s, err := c.FirstSyncData()
+	if assert.NoError(t, err) {
+		assert.Len(t, s.Ewons[0].Tags[0].History, 10000)
+		assert.Empty(t, s.Meta.Header.Get("Content-Encoding"))
+	}
+
+	c = newTestDMWebClient(func(req *http.Request) *http.Response {
+		return gzipResponse(404, []byte(`{"success":false,"code":404,"message":"No eWON found for id '1'"}`))
+	})
+	_, err = c.GetEwonByID(1)
+	assert.EqualError(t, err, "No eWON found for id '1'")
+
+	c = newTestDMWebClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "identity", req.Header.Get("Accept-Encoding"))
+		return jsonResponse(200, `{"historyCount":0,"ewonsCount":0,"ewons":[]}`)
+	})
+	assert.NoError(t, WithCompression(false)(c))
+	_, err = c.GetStatus()
+	assert.NoError(t, err)
+}
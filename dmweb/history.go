@@ -0,0 +1,55 @@
+package dmweb
+
+// flattenHistory returns the history of every tag of every eWON, in
+// order. The history slices are shared with ewons.
+func flattenHistory(ewons []DataEwon) []TagHistory {
+	var hs []TagHistory
+	for _, e := range ewons {
+		for _, t := range e.Tags {
+			hs = append(hs, TagHistory{
+				EwonID:   e.ID,
+				EwonName: e.Name,
+				TagID:    t.ID,
+				TagName:  t.Name,
+				History:  t.History,
+			})
+		}
+	}
+	return hs
+}
+
+// FlattenHistory returns the history of every tag in the response,
+// identified by eWON and tag, in response order.
+func (s *SyncResponse) FlattenHistory() []TagHistory {
+	return flattenHistory(s.Ewons)
+}
+
+// FlattenHistory returns the history of every tag in the response,
+// identified by eWON and tag, in response order.
+func (d *GetDataResponse) FlattenHistory() []TagHistory {
+	return flattenHistory(d.Ewons)
+}
+
+// historyPointCount returns the number of history points of every tag of
+// every eWON.
+func historyPointCount(ewons []DataEwon) int {
+	n := 0
+	for _, e := range ewons {
+		for _, t := range e.Tags {
+			n += len(t.History)
+		}
+	}
+	return n
+}
+
+// HistoryPointCount returns the number of history points in the
+// response, across all eWONs and tags.
+func (s *SyncResponse) HistoryPointCount() int {
+	return historyPointCount(s.Ewons)
+}
+
+// HistoryPointCount returns the number of history points in the
+// response, across all eWONs and tags.
+func (d *GetDataResponse) HistoryPointCount() int {
+	return historyPointCount(d.Ewons)
+}
@@ -0,0 +1,61 @@
+package dmweb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenHistory(t *testing.T) {
+	var s SyncResponse
+	err := json.Unmarshal([]byte(`{"success":true,"transactionId":"1","ewons":[
+		{"id":1,"name":"Ewon1","tags":[
+			{"id":10,"name":"A","history":[{"date":"2018-11-08T14:17:58Z","value":1,"quality":"good"}]},
+			{"id":11,"name":"B","history":[{"date":"2018-11-08T14:17:58Z","value":2},{"date":"2018-11-08T14:18:00Z","value":3}]}
+		]},
+		{"id":2,"name":"Ewon2","tags":[{"id":20,"name":"C"}]}
+	]}`), &s)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, s.HistoryPointCount())
+	assert.Equal(t, 3, (&GetDataResponse{Ewons: s.Ewons}).HistoryPointCount())
+	assert.Equal(t, 0, (&SyncResponse{}).HistoryPointCount())
+
+	d, _ := time.Parse(time.RFC3339, "2018-11-08T14:17:58Z")
+	hs := s.FlattenHistory()
+	if assert.Len(t, hs, 3) {
+		assert.Equal(t, TagHistory{
+			EwonID:   1,
+			EwonName: "Ewon1",
+			TagID:    10,
+			TagName:  "A",
+			History:  []HistoryPoint{{Date: d, Value: 1, Quality: "good"}},
+		}, hs[0])
+		assert.Equal(t, 11, hs[1].TagID)
+		assert.Len(t, hs[1].History, 2)
+		assert.Equal(t, 2, hs[2].EwonID)
+		assert.Empty(t, hs[2].History)
+	}
+}
+
+// sumHistory shows that named types can be passed around.
+func sumHistory(e DataEwon) float64 {
+	var sum float64
+	for _, t := range e.Tags {
+		for _, h := range t.History {
+			sum += h.Value
+		}
+	}
+	return sum
+}
+
+func TestNamedDataTypes(t *testing.T) {
+	var d GetDataResponse
+	err := json.Unmarshal([]byte(`{"success":true,"ewons":[{"id":1,"name":"Ewon1","tags":[
+		{"id":10,"name":"A","history":[{"date":"2018-11-08T14:17:58Z","value":1.5},{"date":"2018-11-08T14:18:00Z","value":2}]}
+	]}]}`), &d)
+	assert.NoError(t, err)
+	assert.Equal(t, 3.5, sumHistory(d.Ewons[0]))
+}
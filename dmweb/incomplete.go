@@ -0,0 +1,86 @@
+package dmweb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// fieldChecker is implemented by the responses whose required fields
+// are checked WithResponseValidation.
+type fieldChecker interface {
+	// missingFields appends the paths of the missing required fields,
+	// prefixed with prefix, to missing.
+	missingFields(prefix string, missing []string) []string
+}
+
+// checkFields returns an error wrapping ErrIncompleteResponse listing
+// the missing required fields of v, or nil.
+func checkFields(v fieldChecker) error {
+	missing := v.missingFields("", nil)
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: missing %s", ErrIncompleteResponse, strings.Join(missing, ", "))
+}
+
+func (e *Ewon) missingFields(prefix string, missing []string) []string {
+	if e.ID == 0 {
+		missing = append(missing, prefix+"id")
+	}
+	if e.Name == "" {
+		missing = append(missing, prefix+"name")
+	}
+	for i, t := range e.Tags {
+		if t == nil || t.ID == 0 {
+			missing = append(missing, fmt.Sprintf("%stags[%d].id", prefix, i))
+		}
+	}
+	return missing
+}
+
+func (r *ewonsResponse) missingFields(prefix string, missing []string) []string {
+	for i, e := range r.Ewons {
+		p := fmt.Sprintf("%sewons[%d].", prefix, i)
+		if e == nil {
+			missing = append(missing, p+"id", p+"name")
+			continue
+		}
+		missing = e.missingFields(p, missing)
+	}
+	return missing
+}
+
+func (s *GetStatusResponse) missingFields(prefix string, missing []string) []string {
+	for i, e := range s.Ewons {
+		if e.ID == 0 {
+			missing = append(missing, fmt.Sprintf("%sewons[%d].id", prefix, i))
+		}
+	}
+	return missing
+}
+
+func (d *GetDataResponse) missingFields(prefix string, missing []string) []string {
+	return dataEwonsMissingFields(d.Ewons, prefix, missing)
+}
+
+func (s *SyncResponse) missingFields(prefix string, missing []string) []string {
+	return dataEwonsMissingFields(s.Ewons, prefix, missing)
+}
+
+func dataEwonsMissingFields(es []DataEwon, prefix string, missing []string) []string {
+	for i, e := range es {
+		p := fmt.Sprintf("%sewons[%d].", prefix, i)
+		if e.ID == 0 {
+			missing = append(missing, p+"id")
+		}
+		if e.Name == "" {
+			missing = append(missing, p+"name")
+		}
+		for j, t := range e.Tags {
+			if t.ID == 0 {
+				missing = append(missing, fmt.Sprintf("%stags[%d].id", p, j))
+			}
+		}
+	}
+	return missing
+}
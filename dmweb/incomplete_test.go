@@ -0,0 +1,42 @@
+package dmweb
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseValidation(t *testing.T) {
+	body := `{"success":true,"ewons":[{"id":1,"name":"a","tags":[{"id":10},{"name":"x"}]},{"name":"b"}]}`
+	client := func(opts ...Option) *Client {
+		c, err := New(NewTestClient(func(req *http.Request) *http.Response {
+			return jsonResponse(200, body)
+		}), "aid", "username", "password", "devid", opts...)
+		assert.NoError(t, err)
+		return c
+	}
+
+	es, err := client().GetEwons()
+	assert.NoError(t, err)
+	assert.Len(t, es, 2)
+
+	c := client(WithResponseValidation())
+	_, err = c.GetEwons()
+	assert.True(t, errors.Is(err, ErrIncompleteResponse))
+	assert.EqualError(t, err, "incomplete response: missing ewons[0].tags[1].id, ewons[1].id")
+
+	d, err := c.GetDataWithParams(GetDataParams{})
+	assert.True(t, errors.Is(err, ErrIncompleteResponse))
+	assert.EqualError(t, err, "incomplete response: missing ewons[0].tags[1].id, ewons[1].id")
+	assert.Len(t, d.Ewons, 2)
+
+	body = `{"id":1,"tags":[{"id":10}]}`
+	_, err = c.GetEwonByID(1)
+	assert.EqualError(t, err, "incomplete response: missing name")
+
+	body = `{"historyCount":0,"ewonsCount":1,"ewons":[{"id":2}]}`
+	_, err = c.GetStatus()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,62 @@
+package dmweb
+
+// LatestValues reduces the response to the latest value of every tag.
+// This is the newest point of the tag's history or, for tags without
+// history, the current value the tag reports, dated with the last
+// synchronization of its eWON.
+func LatestValues(resp *GetDataResponse) []Record {
+	var rs []Record
+	for _, e := range resp.Ewons {
+		for _, t := range e.Tags {
+			r := Record{
+				EwonID:   e.ID,
+				EwonName: e.Name,
+				TagID:    t.ID,
+				TagName:  t.Name,
+				DataType: t.DataType,
+				Date:     e.LastSynchroDate,
+				Value:    t.Value,
+				Quality:  t.Quality,
+			}
+			for i, h := range t.History {
+				if i == 0 || !h.Date.Before(r.Date) {
+					r.Date = h.Date
+					r.Value = h.Value
+					r.Quality = h.Quality
+				}
+			}
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// LatestValueMap returns the latest value of every numeric tag in the
+// response, keyed by "ewonName/tagName", e.g. for a current-state cache.
+// Tags of data type String are skipped. Keys are not guaranteed to be
+// unique: when two eWONs share a name, or names contain a slash, the
+// tag that comes last in the response wins.
+func LatestValueMap(resp *GetDataResponse) map[string]float64 {
+	m := make(map[string]float64)
+	for _, r := range LatestValues(resp) {
+		if r.DataType == DataTypeString {
+			continue
+		}
+		m[r.EwonName+"/"+r.TagName] = r.Value
+	}
+	return m
+}
+
+// ForEachTag calls fn for every tag of every eWON in the response, in
+// response order, together with the ID and name of its eWON. It stops
+// at and returns the first error fn returns.
+func ForEachTag(resp *GetDataResponse, fn func(ewonID int, ewonName string, tag DataTag) error) error {
+	for _, e := range resp.Ewons {
+		for _, t := range e.Tags {
+			if err := fn(e.ID, e.Name, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
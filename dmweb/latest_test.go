@@ -0,0 +1,85 @@
+package dmweb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatestValueMap(t *testing.T) {
+	var d GetDataResponse
+	err := json.Unmarshal([]byte(`{
+		"success": true,
+		"ewons": [{
+			"id": 1,
+			"name": "Ewon1",
+			"tags": [{
+				"id": 10,
+				"name": "Temperature",
+				"dataType": "Float",
+				"value": 7,
+				"history": [
+					{"date": "2018-11-08T14:18:02Z", "value": 5},
+					{"date": "2018-11-08T14:18:04Z", "value": 6},
+					{"date": "2018-11-08T14:17:58Z", "value": 4}
+				]
+			}, {
+				"id": 11,
+				"name": "Label",
+				"dataType": "String",
+				"value": 0
+			}, {
+				"id": 12,
+				"name": "Running",
+				"dataType": "Bool",
+				"value": 1
+			}],
+			"lastSynchroDate": "2018-11-09T09:47:00Z"
+		}, {
+			"id": 2,
+			"name": "Ewon2",
+			"tags": [{
+				"id": 20,
+				"name": "Temperature",
+				"dataType": "Float",
+				"history": [{"date": "2018-11-08T14:17:58Z", "value": 9}]
+			}]
+		}]
+	}`), &d)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]float64{
+		"Ewon1/Temperature": 6,
+		"Ewon1/Running":     1,
+		"Ewon2/Temperature": 9,
+	}, LatestValueMap(&d))
+}
+
+func TestForEachTag(t *testing.T) {
+	var d GetDataResponse
+	err := json.Unmarshal([]byte(`{"success":true,"ewons":[
+		{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"A"},{"id":11,"name":"B"}]},
+		{"id":2,"name":"Ewon2","tags":[{"id":20,"name":"C"}]}
+	]}`), &d)
+	assert.NoError(t, err)
+
+	var visited []string
+	err = ForEachTag(&d, func(ewonID int, ewonName string, tag DataTag) error {
+		visited = append(visited, fmt.Sprintf("%d/%s/%d/%s", ewonID, ewonName, tag.ID, tag.Name))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1/Ewon1/10/A", "1/Ewon1/11/B", "2/Ewon2/20/C"}, visited)
+
+	stop := errors.New("stop")
+	n := 0
+	err = ForEachTag(&d, func(int, string, DataTag) error {
+		n++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, n)
+}
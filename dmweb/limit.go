@@ -0,0 +1,46 @@
+package dmweb
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxResponseSize is the default maximum size of a response body,
+// after decompression, see WithMaxResponseSize.
+const DefaultMaxResponseSize = 512 << 20
+
+// ErrResponseTooLarge is returned when reading a response body larger
+// than the maximum response size, see WithMaxResponseSize.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// limitedBody fails with ErrResponseTooLarge once more than n bytes are
+// read from the body.
+type limitedBody struct {
+	io.ReadCloser
+	n int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.n < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	if int64(len(p)) > b.n+1 {
+		p = p[:b.n+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.n -= int64(n)
+	if b.n < 0 {
+		return n + int(b.n), ErrResponseTooLarge
+	}
+	return n, err
+}
+
+// limitBody bounds the body of res to the maximum response size.
+func (c *Client) limitBody(res *http.Response) {
+	max := c.maxResponseSize
+	if max <= 0 {
+		max = DefaultMaxResponseSize
+	}
+	res.Body = &limitedBody{ReadCloser: res.Body, n: max}
+}
@@ -0,0 +1,43 @@
+package dmweb
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMaxResponseSize(t *testing.T) {
+	body := `{"historyCount":0,"ewonsCount":0,"ewons":[]}`
+	h := NewTestClient(func(req *http.Request) *http.Response {
+		return gzipResponse(200, []byte(body))
+	})
+
+	c, err := New(h, "aid", "username", "password", "devid", WithMaxResponseSize(int64(len(body))))
+	assert.NoError(t, err)
+	_, err = c.GetStatus()
+	assert.NoError(t, err)
+
+	c, err = New(h, "aid", "username", "password", "devid", WithMaxResponseSize(int64(len(body)-1)))
+	assert.NoError(t, err)
+	_, err = c.GetStatus()
+	assert.True(t, errors.Is(err, ErrResponseTooLarge))
+
+	_, err = New(nil, "aid", "username", "password", "devid", WithMaxResponseSize(0))
+	assert.Error(t, err)
+}
+
+func TestLimitedBody(t *testing.T) {
+	b := &limitedBody{ReadCloser: ioutil.NopCloser(strings.NewReader("abcdef")), n: 4}
+	got, err := ioutil.ReadAll(b)
+	assert.Equal(t, ErrResponseTooLarge, err)
+	assert.Equal(t, "abcd", string(got))
+
+	b = &limitedBody{ReadCloser: ioutil.NopCloser(strings.NewReader("abcd")), n: 4}
+	got, err = ioutil.ReadAll(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", string(got))
+}
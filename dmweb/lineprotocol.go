@@ -0,0 +1,69 @@
+package dmweb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	lpMeasurementEscaper = strings.NewReplacer(`,`, `\,`, ` `, `\ `)
+	lpTagEscaper         = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `)
+)
+
+// lineProtocol converts records to InfluxDB line protocol, one line per
+// record, e.g.
+//
+//	ewon_data,ewon=ltn_flexy,tag=TAG_2 value=1510 1541686686000000000
+//
+// Points that aren't of good quality carry a quality tag, e.g.
+// quality=bad. InfluxDB can't store NaN or infinite values, nor empty tag
+// values, so records with those are an error.
+func lineProtocol(measurement string, records []Record) ([]byte, error) {
+	if measurement == "" {
+		return nil, errors.New("measurement must not be empty")
+	}
+	m := lpMeasurementEscaper.Replace(measurement)
+	var buf bytes.Buffer
+	for _, r := range records {
+		if r.EwonName == "" || r.TagName == "" {
+			return nil, fmt.Errorf("ewon %d tag %d: ewon and tag names must not be empty", r.EwonID, r.TagID)
+		}
+		if math.IsNaN(r.Value) || math.IsInf(r.Value, 0) {
+			return nil, fmt.Errorf("ewon %q tag %q: value %v can't be written", r.EwonName, r.TagName, r.Value)
+		}
+		buf.WriteString(m)
+		buf.WriteString(",ewon=")
+		buf.WriteString(lpTagEscaper.Replace(r.EwonName))
+		if r.Quality != "" && !r.Quality.IsGood() {
+			buf.WriteString(",quality=")
+			buf.WriteString(lpTagEscaper.Replace(string(r.Quality)))
+		}
+		buf.WriteString(",tag=")
+		buf.WriteString(lpTagEscaper.Replace(r.TagName))
+		buf.WriteString(" value=")
+		buf.WriteString(strconv.FormatFloat(r.Value, 'g', -1, 64))
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.FormatInt(r.Date.UnixNano(), 10))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// ToLineProtocol converts the history of every tag in the response to
+// InfluxDB line protocol in the given measurement, ready to be posted to
+// the write endpoint of InfluxDB. The eWON and tag names are tags, the
+// value is the value field and the timestamp is in nanoseconds; points
+// that aren't of good quality carry their quality as a tag.
+func (s *SyncResponse) ToLineProtocol(measurement string) ([]byte, error) {
+	return lineProtocol(measurement, s.Records())
+}
+
+// ToLineProtocol converts the history of every tag in the response to
+// InfluxDB line protocol, like SyncResponse.ToLineProtocol.
+func (d *GetDataResponse) ToLineProtocol(measurement string) ([]byte, error) {
+	return lineProtocol(measurement, d.Records())
+}
@@ -0,0 +1,38 @@
+package dmweb
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToLineProtocol(t *testing.T) {
+	d := time.Date(2018, 11, 8, 14, 18, 6, 0, time.UTC)
+	s := &SyncResponse{Ewons: []DataEwon{{ID: 1, Name: "ltn flexy", Tags: []DataTag{
+		{ID: 10, Name: "TAG_2", History: []HistoryPoint{
+			{Date: d, Value: 1510, Quality: QualityGood},
+			{Date: d.Add(time.Second), Value: 1.5, Quality: QualityBad},
+		}},
+		{ID: 11, Name: "a=b,c", History: []HistoryPoint{{Date: d, Value: -2}}},
+	}}}}
+
+	b, err := s.ToLineProtocol("ewon data")
+	assert.NoError(t, err)
+	assert.Equal(t, `ewon\ data,ewon=ltn\ flexy,tag=TAG_2 value=1510 1541686686000000000
+ewon\ data,ewon=ltn\ flexy,quality=bad,tag=TAG_2 value=1.5 1541686687000000000
+ewon\ data,ewon=ltn\ flexy,tag=a\=b\,c value=-2 1541686686000000000
+`, string(b))
+
+	_, err = s.ToLineProtocol("")
+	assert.Error(t, err)
+
+	s.Ewons[0].Tags[1].History[0].Value = math.NaN()
+	_, err = s.ToLineProtocol("ewon_data")
+	assert.EqualError(t, err, `ewon "ltn flexy" tag "a=b,c": value NaN can't be written`)
+
+	g := &GetDataResponse{Ewons: []DataEwon{{ID: 1, Tags: []DataTag{{ID: 10, Name: "TAG", History: []HistoryPoint{{Date: d}}}}}}}
+	_, err = g.ToLineProtocol("ewon_data")
+	assert.EqualError(t, err, "ewon 1 tag 10: ewon and tag names must not be empty")
+}
@@ -0,0 +1,25 @@
+package dmweb
+
+// Logger is the interface of the logger the client reports its
+// internal decisions to, see WithLogger. It is satisfied by most
+// leveled loggers, e.g. *zap.SugaredLogger.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// nopLogger is the Logger used when none is configured.
+type nopLogger struct{}
+
+func (nopLogger) Debugf(format string, args ...interface{}) {}
+func (nopLogger) Warnf(format string, args ...interface{})  {}
+func (nopLogger) Errorf(format string, args ...interface{}) {}
+
+// log returns the configured logger, or a no-op one.
+func (c *Client) log() Logger {
+	if c.logger == nil {
+		return nopLogger{}
+	}
+	return c.logger
+}
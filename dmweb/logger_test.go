@@ -0,0 +1,50 @@
+package dmweb
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *testLogger) logf(level, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, level+" "+fmt.Sprintf(format, args...))
+}
+
+func (l *testLogger) Debugf(format string, args ...interface{}) { l.logf("DEBUG", format, args...) }
+func (l *testLogger) Warnf(format string, args ...interface{})  { l.logf("WARN", format, args...) }
+func (l *testLogger) Errorf(format string, args ...interface{}) { l.logf("ERROR", format, args...) }
+
+func TestWithLogger(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(503, `{"success":false,"code":503,"message":"Service unavailable"}`)
+	})
+	l := &testLogger{}
+	assert.NoError(t, WithLogger(l)(c))
+	assert.NoError(t, WithRetry(1, time.Millisecond)(c))
+
+	_, err := c.GetStatus()
+	assert.Error(t, err)
+	if assert.Len(t, l.lines, 4) {
+		assert.True(t, strings.HasPrefix(l.lines[0], "DEBUG dmweb: GET getstatus failed in "))
+		assert.True(t, strings.HasPrefix(l.lines[1], "WARN dmweb: GET getstatus failed, retrying in "))
+		assert.True(t, strings.HasPrefix(l.lines[2], "DEBUG dmweb: GET getstatus failed in "))
+		assert.Equal(t, "ERROR dmweb: GET getstatus failed after 2 attempts: Service unavailable", l.lines[3])
+	}
+	for _, line := range l.lines {
+		assert.NotContains(t, line, "password")
+	}
+
+	assert.Error(t, WithLogger(nil)(c))
+}
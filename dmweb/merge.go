@@ -0,0 +1,58 @@
+package dmweb
+
+import "sort"
+
+// MergeSyncResponses merges pages, e.g. as returned by SyncAllData, into
+// a single response. eWONs are merged by ID and their tags by ID, like
+// Dedupe, in the order they first appear; the history of every tag is
+// the concatenation of its history on all pages, sorted by date. Duplicate
+// points are kept, see Dedupe. The transaction and MoreDataAvailable of
+// the merged response are those of the last page. The pages are not
+// modified.
+func MergeSyncResponses(pages ...*SyncResponse) *SyncResponse {
+	out := &SyncResponse{Success: true}
+	ewons := make(map[int]int)
+	tags := make(map[tagKey]int)
+	for _, p := range pages {
+		if p == nil {
+			continue
+		}
+		out.Success = out.Success && p.Success
+		out.TransactionID = p.TransactionID
+		out.MoreDataAvailable = p.MoreDataAvailable
+		out.Meta = p.Meta
+		for _, e := range p.Ewons {
+			ei, ok := ewons[e.ID]
+			if !ok {
+				ei = len(out.Ewons)
+				ewons[e.ID] = ei
+				ne := e
+				ne.Tags = nil
+				out.Ewons = append(out.Ewons, ne)
+			}
+			oe := &out.Ewons[ei]
+			if e.LastSynchroDate.After(oe.LastSynchroDate) {
+				oe.LastSynchroDate = e.LastSynchroDate
+			}
+			for _, t := range e.Tags {
+				k := tagKey{ewonID: e.ID, tagID: t.ID}
+				ti, ok := tags[k]
+				if !ok {
+					ti = len(oe.Tags)
+					tags[k] = ti
+					nt := t
+					nt.History = nil
+					oe.Tags = append(oe.Tags, nt)
+				}
+				oe.Tags[ti].History = append(oe.Tags[ti].History, t.History...)
+			}
+		}
+	}
+	for i := range out.Ewons {
+		for j := range out.Ewons[i].Tags {
+			h := out.Ewons[i].Tags[j].History
+			sort.SliceStable(h, func(a, b int) bool { return h[a].Date.Before(h[b].Date) })
+		}
+	}
+	return out
+}
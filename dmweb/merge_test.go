@@ -0,0 +1,52 @@
+package dmweb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeSyncResponses(t *testing.T) {
+	d := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	p1 := &SyncResponse{Success: true, TransactionID: "1", MoreDataAvailable: true, Ewons: []DataEwon{
+		{ID: 1, Name: "Ewon1", Tags: []DataTag{
+			{ID: 10, EwonTagID: 1, Name: "A", History: []HistoryPoint{{Date: d.Add(time.Minute), Value: 2}}},
+		}},
+	}}
+	p2 := &SyncResponse{Success: true, TransactionID: "2", Ewons: []DataEwon{
+		{ID: 2, Name: "Ewon2", Tags: []DataTag{{ID: 20, EwonTagID: 1, Name: "A"}}},
+		{ID: 1, Name: "Ewon1", LastSynchroDate: d, Tags: []DataTag{
+			{ID: 11, EwonTagID: 2, Name: "B", History: []HistoryPoint{{Date: d, Value: 5}}},
+			{ID: 10, EwonTagID: 1, Name: "A", History: []HistoryPoint{{Date: d, Value: 1}}},
+		}},
+	}}
+
+	s := MergeSyncResponses(p1, nil, p2)
+	assert.True(t, s.Success)
+	assert.Equal(t, "2", s.TransactionID)
+	assert.False(t, s.MoreDataAvailable)
+	if assert.Len(t, s.Ewons, 2) {
+		e := s.Ewons[0]
+		assert.Equal(t, 1, e.ID)
+		assert.Equal(t, d, e.LastSynchroDate)
+		if assert.Len(t, e.Tags, 2) {
+			assert.Equal(t, "A", e.Tags[0].Name)
+			assert.Equal(t, []HistoryPoint{{Date: d, Value: 1}, {Date: d.Add(time.Minute), Value: 2}}, e.Tags[0].History)
+			assert.Equal(t, "B", e.Tags[1].Name)
+		}
+		assert.Equal(t, 2, s.Ewons[1].ID)
+	}
+	assert.Len(t, p1.Ewons[0].Tags[0].History, 1)
+
+	assert.Empty(t, MergeSyncResponses().Ewons)
+
+	// Tags without an ewonTagId stay apart.
+	s = MergeSyncResponses(&SyncResponse{Ewons: []DataEwon{{ID: 1, Tags: []DataTag{
+		{ID: 10, Name: "A"},
+		{ID: 11, Name: "B"},
+	}}}})
+	if assert.Len(t, s.Ewons, 1) {
+		assert.Len(t, s.Ewons[0].Tags, 2)
+	}
+}
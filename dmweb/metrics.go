@@ -0,0 +1,27 @@
+package dmweb
+
+import "time"
+
+// Metrics is the interface the client reports requests to, see
+// WithMetrics. It is easily adapted to a metrics library, e.g. with a
+// Prometheus counter and histogram labeled by endpoint and status:
+//
+//	type promMetrics struct {
+//		requests *prometheus.CounterVec
+//		latency  *prometheus.HistogramVec
+//	}
+//
+//	func (m promMetrics) ObserveRequest(endpoint string, status int, d time.Duration, err error) {
+//		s := strconv.Itoa(status)
+//		m.requests.WithLabelValues(endpoint, s).Inc()
+//		m.latency.WithLabelValues(endpoint, s).Observe(d.Seconds())
+//	}
+//
+// Implementations must be safe for concurrent use, as batch operations
+// send requests from multiple goroutines.
+type Metrics interface {
+	// ObserveRequest is called after every request to the endpoint
+	// name, with the status of the response, or 0 if none was received,
+	// the time it took and its error, if any.
+	ObserveRequest(endpoint string, status int, d time.Duration, err error)
+}
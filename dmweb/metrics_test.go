@@ -0,0 +1,52 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMetrics struct {
+	mu       sync.Mutex
+	requests map[string]int
+	errors   int
+}
+
+func (m *testMetrics) ObserveRequest(endpoint string, status int, d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[fmt.Sprintf("%s %d", endpoint, status)]++
+	if err != nil {
+		m.errors++
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		if req.URL.Query().Get("id") == "3" {
+			return jsonResponse(404, `{"success":false,"code":404,"message":"No eWON found for id '3'"}`)
+		}
+		return jsonResponse(200, `{"success":true,"id":1}`)
+	})
+	m := &testMetrics{requests: make(map[string]int)}
+	assert.NoError(t, WithMetrics(m)(c))
+
+	c.GetEwonsByIDs(context.Background(), []int{1, 2, 3}, 3)
+	assert.Equal(t, map[string]int{"getewon 200": 2, "getewon 404": 1}, m.requests)
+	assert.Equal(t, 1, m.errors)
+
+	c.Client.Transport = transportFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	_, err := c.GetStatus()
+	assert.Error(t, err)
+	assert.Equal(t, 1, m.requests["getstatus 0"])
+
+	assert.Error(t, WithMetrics(nil)(c))
+}
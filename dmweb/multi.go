@@ -0,0 +1,70 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MultiClient makes requests to several Talk2M accounts, with a Client
+// per account, e.g. for a unified view of the eWONs of all customers of
+// a managed service.
+type MultiClient struct {
+	clients []*Client
+}
+
+// NewMultiClient returns a MultiClient for clients, which must have
+// distinct, non-empty account IDs, as results are keyed by account ID.
+func NewMultiClient(clients ...*Client) (*MultiClient, error) {
+	seen := make(map[string]bool, len(clients))
+	for _, c := range clients {
+		if c == nil {
+			return nil, errors.New("client must not be nil")
+		}
+		if c.AccountID == "" {
+			return nil, errors.New("client has no account ID")
+		}
+		if seen[c.AccountID] {
+			return nil, fmt.Errorf("duplicate account ID %q", c.AccountID)
+		}
+		seen[c.AccountID] = true
+	}
+	return &MultiClient{clients: clients}, nil
+}
+
+// Clients returns the clients of m.
+func (m *MultiClient) Clients() []*Client {
+	return m.clients
+}
+
+// GetAllEwons lists the eWONs of every account concurrently, keyed by
+// account ID. A failing account doesn't abort the others: it is left
+// out of the result and reported as AccountErrors.
+func (m *MultiClient) GetAllEwons(ctx context.Context) (map[string]Ewons, error) {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		out  = make(map[string]Ewons, len(m.clients))
+		errs = make(AccountErrors)
+	)
+	for _, c := range m.clients {
+		wg.Add(1)
+		go func(c *Client) {
+			defer wg.Done()
+			es, err := c.GetEwonsContext(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[c.AccountID] = err
+				return
+			}
+			out[c.AccountID] = es
+		}(c)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return out, errs
+	}
+	return out, nil
+}
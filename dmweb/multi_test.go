@@ -0,0 +1,45 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiClient(t *testing.T) {
+	client := func(accountID string) *Client {
+		c, err := New(NewTestClient(func(req *http.Request) *http.Response {
+			if req.URL.Query().Get("t2maccount") == "broken" {
+				return jsonResponse(401, `{"success":false,"code":401,"message":"Invalid credentials"}`)
+			}
+			return jsonResponse(200, `{"success":true,"ewons":[{"id":1,"name":"`+accountID+`"}]}`)
+		}), accountID, "username", "password", "devid")
+		assert.NoError(t, err)
+		return c
+	}
+
+	m, err := NewMultiClient(client("a"), client("b"), client("broken"))
+	assert.NoError(t, err)
+	assert.Len(t, m.Clients(), 3)
+	es, err := m.GetAllEwons(context.Background())
+	var errs AccountErrors
+	if assert.True(t, errors.As(err, &errs)) && assert.Len(t, errs, 1) {
+		assert.True(t, IsAuthError(errs["broken"]))
+	}
+	assert.Len(t, es, 2)
+	assert.Equal(t, "a", es["a"][0].Name)
+	assert.Equal(t, "b", es["b"][0].Name)
+
+	m, err = NewMultiClient(client("a"))
+	assert.NoError(t, err)
+	_, err = m.GetAllEwons(context.Background())
+	assert.NoError(t, err)
+
+	_, err = NewMultiClient(client("a"), client("a"))
+	assert.EqualError(t, err, `duplicate account ID "a"`)
+	_, err = NewMultiClient(nil)
+	assert.Error(t, err)
+}
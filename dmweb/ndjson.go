@@ -0,0 +1,66 @@
+package dmweb
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// writeNDJSON writes a Record per history point of es to w, flushing w
+// after every tag when it can be flushed.
+func writeNDJSON(w io.Writer, es []DataEwon) error {
+	enc := json.NewEncoder(w)
+	for _, e := range es {
+		for _, t := range e.Tags {
+			for _, h := range t.History {
+				r := Record{
+					EwonID:   e.ID,
+					EwonName: e.Name,
+					TagID:    t.ID,
+					TagName:  t.Name,
+					DataType: h.DataType,
+					Date:     h.Date,
+					Value:    h.Value,
+					Quality:  h.Quality,
+				}
+				if err := enc.Encode(r); err != nil {
+					return err
+				}
+			}
+			if err := flush(w); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// flush flushes w if it buffers, like a *bufio.Writer or an
+// http.ResponseWriter.
+func flush(w io.Writer) error {
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case http.Flusher:
+		f.Flush()
+	}
+	return nil
+}
+
+// WriteNDJSON writes the history of every tag in the response to w as
+// newline-delimited JSON: a Record per history point, e.g.
+//
+//	{"ewonId":1,"ewonName":"Paris","tagId":10,"tagName":"temp","date":"2018-11-08T14:17:58Z","value":21.5,"quality":"good"}
+//
+// The points are written as they are encoded rather than buffered, and w
+// is flushed after every tag when it has a Flush method, like a
+// *bufio.Writer or an http.ResponseWriter.
+func (s *SyncResponse) WriteNDJSON(w io.Writer) error {
+	return writeNDJSON(w, s.Ewons)
+}
+
+// WriteNDJSON writes the history of every tag in the response to w as
+// newline-delimited JSON, like SyncResponse.WriteNDJSON.
+func (d *GetDataResponse) WriteNDJSON(w io.Writer) error {
+	return writeNDJSON(w, d.Ewons)
+}
@@ -0,0 +1,32 @@
+package dmweb
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteNDJSON(t *testing.T) {
+	t0 := time.Date(2018, 11, 8, 14, 17, 58, 0, time.UTC)
+	s := &SyncResponse{Ewons: []DataEwon{{
+		ID:   1,
+		Name: "Paris",
+		Tags: []DataTag{
+			{ID: 10, Name: "temp", History: []HistoryPoint{
+				{Date: t0, Value: 21.5, Quality: QualityGood},
+				{Date: t0.Add(time.Minute), Value: 22},
+			}},
+			{ID: 11, Name: "empty"},
+		},
+	}}}
+
+	var buf bytes.Buffer
+	bw := bufio.NewWriter(&buf)
+	assert.NoError(t, s.WriteNDJSON(bw))
+	assert.Equal(t, `{"ewonId":1,"ewonName":"Paris","tagId":10,"tagName":"temp","date":"2018-11-08T14:17:58Z","value":21.5,"quality":"good"}
+{"ewonId":1,"ewonName":"Paris","tagId":10,"tagName":"temp","date":"2018-11-08T14:18:58Z","value":22}
+`, buf.String())
+}
@@ -0,0 +1,152 @@
+package dmweb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// The number* types decode only the values of a response, as written in
+// the JSON, see WithUseNumber. Their slices line up with those of the
+// response types.
+type (
+	numberPoint struct {
+		Value json.Number `json:"value"`
+	}
+	numberTag struct {
+		Value   json.Number   `json:"value"`
+		History []numberPoint `json:"history"`
+	}
+	numberEwon struct {
+		Tags []numberTag `json:"tags"`
+	}
+	numberResponse struct {
+		Ewons []numberEwon `json:"ewons"`
+		Tags  []numberTag  `json:"tags"`
+	}
+)
+
+// numberSetter is implemented by the responses carrying tag values.
+type numberSetter interface {
+	setNumbers(n *numberResponse)
+}
+
+// setNumbers decodes the values of body and passes them to ns. Values
+// that can't be decoded as numbers are left out, keeping the float64
+// values decoded before.
+func setNumbers(ns numberSetter, body []byte) {
+	var n numberResponse
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&n); err != nil {
+		return
+	}
+	ns.setNumbers(&n)
+}
+
+// ewonsResponse is the response to the getewons endpoint.
+type ewonsResponse struct {
+	Success bool
+	Ewons   Ewons
+}
+
+// ewonResponse is the response to the getewon endpoint, an eWON along
+// with the success flag.
+type ewonResponse struct {
+	Success bool `json:"success"`
+	Ewon
+}
+
+func (r *ewonsResponse) setNumbers(n *numberResponse) {
+	for i, e := range r.Ewons {
+		if i < len(n.Ewons) && e != nil {
+			e.setNumbers(&numberResponse{Tags: n.Ewons[i].Tags})
+		}
+	}
+}
+
+func (e *Ewon) setNumbers(n *numberResponse) {
+	for i, t := range e.Tags {
+		if i < len(n.Tags) && t != nil {
+			t.number = n.Tags[i].Value
+		}
+	}
+}
+
+func (d *GetDataResponse) setNumbers(n *numberResponse) { setDataNumbers(d.Ewons, n) }
+func (s *SyncResponse) setNumbers(n *numberResponse)    { setDataNumbers(s.Ewons, n) }
+
+func setDataNumbers(ewons []DataEwon, n *numberResponse) {
+	for i := range ewons {
+		if i >= len(n.Ewons) {
+			return
+		}
+		tags, nts := ewons[i].Tags, n.Ewons[i].Tags
+		for j := range tags {
+			if j >= len(nts) {
+				break
+			}
+			tags[j].number = nts[j].Value
+			for k := range tags[j].History {
+				if k >= len(nts[j].History) {
+					break
+				}
+				tags[j].History[k].number = nts[j].History[k].Value
+			}
+		}
+	}
+}
+
+// int64Value returns the exact integer value n when it is set, or v
+// otherwise, failing for fractional and out of range values.
+func int64Value(n json.Number, v float64) (int64, error) {
+	if n != "" {
+		return n.Int64()
+	}
+	if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+		return 0, fmt.Errorf("value %v is not an int64", v)
+	}
+	return int64(v), nil
+}
+
+// float64Value returns n as a float64 when it is set, or v otherwise.
+func float64Value(n json.Number, v float64) (float64, error) {
+	if n != "" {
+		return n.Float64()
+	}
+	return v, nil
+}
+
+// Int64Value returns the value of the tag as an int64. Integers beyond
+// 2^53, e.g. of 64-bit counters, are only exact when the client was
+// created WithUseNumber. Fractional values are an error.
+func (t *Tag) Int64Value() (int64, error) {
+	return int64Value(t.number, t.Value)
+}
+
+// Float64Value returns the value of the tag as a float64, parsed from
+// the exact value when the client was created WithUseNumber.
+func (t *Tag) Float64Value() (float64, error) {
+	return float64Value(t.number, t.Value)
+}
+
+// Int64Value is like Tag.Int64Value.
+func (t *DataTag) Int64Value() (int64, error) {
+	return int64Value(t.number, t.Value)
+}
+
+// Float64Value is like Tag.Float64Value.
+func (t *DataTag) Float64Value() (float64, error) {
+	return float64Value(t.number, t.Value)
+}
+
+// Int64Value is like Tag.Int64Value.
+func (p *HistoryPoint) Int64Value() (int64, error) {
+	return int64Value(p.number, p.Value)
+}
+
+// Float64Value is like Tag.Float64Value.
+func (p *HistoryPoint) Float64Value() (float64, error) {
+	return float64Value(p.number, p.Value)
+}
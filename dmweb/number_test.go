@@ -0,0 +1,71 @@
+package dmweb
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithUseNumber(t *testing.T) {
+	h := NewTestClient(func(req *http.Request) *http.Response {
+		switch req.URL.Path {
+		case "/getewon":
+			return jsonResponse(200, `{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"Counter","value":9007199254740993}]}`)
+		case "/getewons":
+			return jsonResponse(200, `{"success":true,"ewons":[{"id":1,"name":"Ewon1","tags":[{"id":10,"name":"Counter","value":9007199254740993}]}]}`)
+		}
+		return jsonResponse(200, `{"success":true,"transactionId":"1","ewons":[{"id":1,"name":"Ewon1","tags":[
+			{"id":10,"name":"Counter","value":9007199254740993,"history":[
+				{"date":"2018-11-08T14:17:58Z","value":9007199254740995},
+				{"date":"2018-11-08T14:18:58Z","value":1.5}]}]}]}`)
+	})
+
+	c, err := New(h, "aid", "username", "password", "devid")
+	assert.NoError(t, err)
+	e, err := c.GetEwonByID(1)
+	assert.NoError(t, err)
+	v, err := e.Tags[0].Int64Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740992), v)
+
+	c, err = New(h, "aid", "username", "password", "devid", WithUseNumber(), WithStrictDecoding())
+	assert.NoError(t, err)
+	e, err = c.GetEwonByID(1)
+	assert.NoError(t, err)
+	v, err = e.Tags[0].Int64Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), v)
+
+	es, err := c.GetEwons()
+	assert.NoError(t, err)
+	v, err = es[0].Tags[0].Int64Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), v)
+
+	s, err := c.FirstSyncData()
+	assert.NoError(t, err)
+	tag := s.Ewons[0].Tags[0]
+	v, err = tag.Int64Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), v)
+	v, err = tag.History[0].Int64Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740995), v)
+	_, err = tag.History[1].Int64Value()
+	assert.Error(t, err)
+	f, err := tag.History[1].Float64Value()
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, f)
+}
+
+func TestInt64ValueWithoutNumber(t *testing.T) {
+	p := HistoryPoint{Value: 42}
+	v, err := p.Int64Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), v)
+
+	p.Value = 1e300
+	_, err = p.Int64Value()
+	assert.Error(t, err)
+}
@@ -0,0 +1,604 @@
+package dmweb
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Option configures a Client created by New.
+type Option func(*Client) error
+
+// WithEndpoint routes requests for the endpoint name (one of the
+// Endpoint constants) to path, relative to the base URL. This allows
+// following renamed or versioned endpoints, e.g. "v2/getdata", without
+// waiting for a new release of this package.
+func WithEndpoint(name, path string) Option {
+	return func(c *Client) error {
+		path = strings.TrimPrefix(path, "/")
+		if name == "" || path == "" {
+			return errors.New("endpoint name and path must not be empty")
+		}
+		if c.endpoints == nil {
+			c.endpoints = make(map[string]string)
+		}
+		c.endpoints[name] = path
+		return nil
+	}
+}
+
+// WithReadBufferSize sets the size of the buffer response bodies are
+// read through while being decoded. Larger buffers mean fewer reads on
+// big payloads. The default is DefaultReadBufferSize.
+func WithReadBufferSize(size int) Option {
+	return func(c *Client) error {
+		if size <= 0 {
+			return errors.New("read buffer size must be positive")
+		}
+		c.readBufferSize = size
+		return nil
+	}
+}
+
+// WithEndpointMethod makes requests to the endpoint name (one of the
+// Endpoint constants) use method, which must be GET or POST. POST sends
+// the parameters, credentials included, in the request body instead of
+// the URL.
+// By default, GET is used for all endpoints, except getdata and syncdata
+// which switch to POST when their parameters get too long for a URL.
+func WithEndpointMethod(name, method string) Option {
+	return func(c *Client) error {
+		if method != http.MethodGet && method != http.MethodPost {
+			return fmt.Errorf("unsupported method %q for endpoint %s", method, name)
+		}
+		if c.methods == nil {
+			c.methods = make(map[string]string)
+		}
+		c.methods[name] = method
+		return nil
+	}
+}
+
+// WithHTTPClientTimeout sets the timeout of the HTTP client New creates
+// when it isn't given one, instead of DefaultHTTPTimeout. It has no
+// effect on a client passed to New.
+func WithHTTPClientTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return errors.New("HTTP client timeout must be positive")
+		}
+		c.httpTimeout = d
+		return nil
+	}
+}
+
+// WithLoggingInterval registers that the tag named tagName is expected
+// to be logged every d, for use by Completeness. The DMWeb API doesn't
+// report how tags are logged, so this has to come from the eWON
+// configuration. It applies to tags of that name on any eWON.
+func WithLoggingInterval(tagName string, d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return errors.New("logging interval must be positive")
+		}
+		if c.loggingIntervals == nil {
+			c.loggingIntervals = make(map[string]time.Duration)
+		}
+		c.loggingIntervals[tagName] = d
+		return nil
+	}
+}
+
+// WithMaxSyncPages caps the number of syncdata requests a single
+// SyncAllData call makes, instead of DefaultMaxSyncPages, to guard
+// against syncing endlessly.
+func WithMaxSyncPages(n int) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return errors.New("max sync pages must be positive")
+		}
+		c.maxSyncPages = n
+		return nil
+	}
+}
+
+// WithBaseURL makes the client talk to the DMWeb API at baseURL instead
+// of DefaultBaseURL, e.g. a staging environment. baseURL must be an
+// absolute URL ending in a slash.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("invalid base URL: %w", err)
+		}
+		if !u.IsAbs() || u.Host == "" {
+			return fmt.Errorf("invalid base URL %q: not an absolute URL", baseURL)
+		}
+		if !strings.HasSuffix(u.Path, "/") || u.RawQuery != "" || u.Fragment != "" {
+			return fmt.Errorf("invalid base URL %q: must end in a slash", baseURL)
+		}
+		c.baseURL = baseURL
+		return nil
+	}
+}
+
+// WithUserAgent replaces the User-Agent header sent with every request,
+// which is DefaultUserAgent by default.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		if userAgent == "" {
+			return errors.New("user agent must not be empty")
+		}
+		c.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithUserAgentSuffix appends suffix, e.g. "my-app/1.2", to the
+// User-Agent header sent with every request, keeping this package's
+// identification.
+func WithUserAgentSuffix(suffix string) Option {
+	return func(c *Client) error {
+		if suffix == "" {
+			return errors.New("user agent suffix must not be empty")
+		}
+		c.userAgent += " " + suffix
+		return nil
+	}
+}
+
+// WithHTTPClient makes the client send requests with h, taking
+// precedence over the HTTP client passed to New. WithProxy and
+// WithTLSConfig apply on top of h, regardless of the order of the
+// options.
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) error {
+		if h == nil {
+			return errors.New("HTTP client must not be nil")
+		}
+		c.Client = h
+		return nil
+	}
+}
+
+// WithRetry retries GET requests up to maxRetries times on connection
+// errors and on 429, 502, 503 and 504 responses, except when the
+// DataMailbox is down for maintenance. The delay between attempts starts
+// at baseDelay and doubles with every attempt, up to 30 seconds or
+// baseDelay if longer, with random jitter, unless the server asks for a
+// specific delay with a Retry-After header.
+// When all attempts fail, the returned error is a *RetryError.
+func WithRetry(maxRetries int, baseDelay time.Duration) Option {
+	return func(c *Client) error {
+		if maxRetries < 0 || baseDelay <= 0 {
+			return errors.New("max retries must not be negative and base delay must be positive")
+		}
+		c.retry = retryPolicy{maxRetries: maxRetries, baseDelay: baseDelay}
+		return nil
+	}
+}
+
+// WithEwonLocalTime makes the client interpret history timestamps as
+// local time of the eWON that logged them, in the time zone the eWON
+// reports. Before firmware 13.2, the eWON always logs data in local time,
+// and as of firmware 13.2 it only logs in UTC when configured to, yet the
+// DataMailbox presents all timestamps as UTC.
+// Timestamps of eWONs that don't report a time zone, or report one that
+// can't be loaded, are left untouched; the latter is reported as a
+// warning, see WithWarningHandler.
+func WithEwonLocalTime() Option {
+	return func(c *Client) error {
+		c.ewonLocalTime = true
+		return nil
+	}
+}
+
+// WithWarningHandler makes the client report conditions that don't fail
+// a request, but may affect its result, to fn.
+func WithWarningHandler(fn func(error)) Option {
+	return func(c *Client) error {
+		c.onWarning = fn
+		return nil
+	}
+}
+
+// WithRateLimit paces all requests of the client, including retries, with
+// a token bucket that refills at r tokens per second and holds up to
+// burst tokens, so that polling loops stay within the Talk2M quotas.
+// Requests wait for a token as long as their context allows.
+// The limiter is shared by all calls on the client, see RateLimiter.
+func WithRateLimit(r rate.Limit, burst int) Option {
+	return func(c *Client) error {
+		if r <= 0 || burst < 1 {
+			return errors.New("rate limit and burst must be positive")
+		}
+		c.limiter = rate.NewLimiter(r, burst)
+		return nil
+	}
+}
+
+// WithRequestHook makes the client call fn with every request it sends,
+// including retries, right before sending it. The request is a copy with
+// the credentials redacted from its URL, see RedactURL; fn must not read
+// its body. Hooks are called in the order they were added.
+func WithRequestHook(fn func(*http.Request)) Option {
+	return func(c *Client) error {
+		if fn == nil {
+			return errors.New("request hook must not be nil")
+		}
+		c.requestHooks = append(c.requestHooks, fn)
+		return nil
+	}
+}
+
+// WithResponseHook makes the client call fn after every request it sends,
+// including retries, with the response, the time it took and the error
+// of the request. The response is nil when the request failed before a
+// response was received; the body of responses other than 200 OK has
+// already been consumed, and the error is then usually an *APIError.
+// The credentials are redacted from the URL of the response's Request.
+// Hooks are called in the order they were added.
+func WithResponseHook(fn func(*http.Response, time.Duration, error)) Option {
+	return func(c *Client) error {
+		if fn == nil {
+			return errors.New("response hook must not be nil")
+		}
+		c.responseHooks = append(c.responseHooks, fn)
+		return nil
+	}
+}
+
+// WithStrictValidation makes New check the format of the account and
+// developer IDs, to catch copy-paste mistakes before the first request:
+// the developer ID must be a UUID and the account ID must not contain
+// whitespace or control characters. Malformed IDs are reported with an
+// error wrapping ErrInvalidCredentials. GetData then also checks its
+// params with ValidateDataParams.
+func WithStrictValidation() Option {
+	return func(c *Client) error {
+		c.strictValidation = true
+		return nil
+	}
+}
+
+// WithDefaultTimeout bounds every request, including retries and reading
+// the response, to d, on top of any deadline of the request's context.
+// Unlike the Timeout of the HTTP client, it can be overridden per
+// endpoint with WithEndpointTimeout.
+func WithDefaultTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return errors.New("timeout must be positive")
+		}
+		c.defaultTimeout = d
+		return nil
+	}
+}
+
+// WithEndpointTimeout bounds requests to the endpoint name (one of the
+// Endpoint constants) to d, overriding WithDefaultTimeout, e.g. to give
+// syncdata more time than getstatus.
+func WithEndpointTimeout(name string, d time.Duration) Option {
+	return func(c *Client) error {
+		if name == "" || d <= 0 {
+			return errors.New("endpoint name must not be empty and timeout must be positive")
+		}
+		if c.timeouts == nil {
+			c.timeouts = make(map[string]time.Duration)
+		}
+		c.timeouts[name] = d
+		return nil
+	}
+}
+
+// WithLogger makes the client log its internal decisions to l: every
+// request at debug level, retries and warnings at warning level, and
+// requests that failed after retrying at error level. Logged endpoints
+// are names, so credentials never end up in the log. By default nothing
+// is logged.
+func WithLogger(l Logger) Option {
+	return func(c *Client) error {
+		if l == nil {
+			return errors.New("logger must not be nil")
+		}
+		c.logger = l
+		return nil
+	}
+}
+
+// WithCaseInsensitiveEwonNames makes GetEwonByName fall back to matching
+// the names of all eWONs case-insensitively when no eWON has exactly the
+// given name, at the cost of an extra getewons request.
+func WithCaseInsensitiveEwonNames() Option {
+	return func(c *Client) error {
+		c.ewonNameFold = true
+		return nil
+	}
+}
+
+// WithMetrics makes the client report every request it sends, including
+// retries, to m.
+func WithMetrics(m Metrics) Option {
+	return func(c *Client) error {
+		if m == nil {
+			return errors.New("metrics must not be nil")
+		}
+		c.metrics = m
+		return nil
+	}
+}
+
+// WithCompression sets whether the client asks for gzipped responses,
+// which shrinks large getdata and syncdata payloads many times over.
+// Compression is enabled by default; disable it when a proxy mishandles
+// it.
+func WithCompression(enabled bool) Option {
+	return func(c *Client) error {
+		c.noCompression = !enabled
+		return nil
+	}
+}
+
+// WithAutoResync makes SyncData start a new transaction, like
+// FirstSyncData, when the DataMailbox rejects the last transaction ID as
+// unknown or expired, instead of returning ErrTransactionExpired. As a
+// new transaction starts with the oldest data in the DataMailbox, data
+// that was synced before may be returned again. The expiry is reported
+// as a warning, see WithWarningHandler. When the error code was caused
+// by another invalid parameter, the new transaction fails with it too,
+// and that error is returned.
+func WithAutoResync() Option {
+	return func(c *Client) error {
+		c.autoResync = true
+		return nil
+	}
+}
+
+// WithCredentialProvider makes the client ask p for the credentials of
+// every request, with the request's context, instead of using the
+// credentials it was constructed with. The token is used when p provides
+// one, the username and password otherwise. Errors of p are returned by
+// the request. WithStrictValidation doesn't apply to provided
+// credentials.
+func WithCredentialProvider(p CredentialProvider) Option {
+	return func(c *Client) error {
+		if p == nil {
+			return errors.New("credential provider must not be nil")
+		}
+		c.auth = providerAuth{p}
+		return nil
+	}
+}
+
+// WithCache makes the client cache the successful responses of the
+// getstatus, getewons and getewon endpoints, e.g. for GetStatus, GetEwons
+// and GetEwonByID, for ttl, keyed by endpoint and parameters. The data
+// endpoints getdata and syncdata are never cached. See InvalidateCache.
+func WithCache(ttl time.Duration) Option {
+	return func(c *Client) error {
+		if ttl <= 0 {
+			return errors.New("cache ttl must be positive")
+		}
+		c.cache = newResponseCache(ttl)
+		return nil
+	}
+}
+
+// WithProxy makes the client send requests through the HTTP proxy at
+// proxyURL, e.g. "http://proxy.example.com:3128", instead of the proxy
+// from the environment.
+// Like WithTLSConfig, it applies to the transport of the HTTP client
+// passed to New or WithHTTPClient, or created by New, whichever is used,
+// overriding that transport's setting. The HTTP client and transport
+// are copied, not modified. The transport must be an *http.Transport,
+// or be nil to use http.DefaultTransport.
+func WithProxy(proxyURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid proxy URL %q: scheme and host are required", proxyURL)
+		}
+		c.proxyURL = u
+		return nil
+	}
+}
+
+// WithTLSConfig makes the client use a copy of cfg for its TLS
+// connections, e.g. to trust the CA of a corporate proxy. See WithProxy
+// for how it applies to the HTTP client.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) error {
+		if cfg == nil {
+			return errors.New("TLS config must not be nil")
+		}
+		c.tlsConfig = cfg
+		return nil
+	}
+}
+
+// WithStrictDecoding makes decoding a response fail on any field the
+// response types don't have, to detect changes of the API early, e.g.
+// in integration tests against a new API version. By default unknown
+// fields are ignored.
+func WithStrictDecoding() Option {
+	return func(c *Client) error {
+		c.strictDecoding = true
+		return nil
+	}
+}
+
+// WithResponseValidation makes decoding a response fail with an error
+// wrapping ErrIncompleteResponse when required fields are missing or
+// zero: the IDs and names of eWONs and the IDs of tags. The decoded
+// response is still returned with the error. This catches partial
+// payloads that decode without error. By default responses aren't
+// validated.
+func WithResponseValidation() Option {
+	return func(c *Client) error {
+		c.validateResponses = true
+		return nil
+	}
+}
+
+// WithUseNumber makes the client keep the values of tags and history
+// points exactly as written in the response, in addition to the float64
+// Value, so that Int64Value returns integers beyond 2^53, e.g. of 64-bit
+// counters, without rounding. It costs decoding the values of each
+// response a second time.
+func WithUseNumber() Option {
+	return func(c *Client) error {
+		c.useNumber = true
+		return nil
+	}
+}
+
+// WithMaxIdleConns limits the number of idle connections the client
+// keeps open to n. Like WithIdleConnTimeout and WithDisableKeepAlives,
+// it only applies to the HTTP client New creates when it isn't given
+// one, and is ignored for an HTTP client passed to New or WithHTTPClient.
+func WithMaxIdleConns(n int) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return errors.New("max idle connections must be positive")
+		}
+		c.maxIdleConns = n
+		return nil
+	}
+}
+
+// WithIdleConnTimeout closes connections that have been idle for d. See
+// WithMaxIdleConns for when it applies.
+func WithIdleConnTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return errors.New("idle connection timeout must be positive")
+		}
+		c.idleConnTimeout = d
+		return nil
+	}
+}
+
+// WithDisableKeepAlives makes the client use a new connection for every
+// request, e.g. on gateways where lingering connections cause problems.
+// See WithMaxIdleConns for when it applies.
+func WithDisableKeepAlives() Option {
+	return func(c *Client) error {
+		c.disableKeepAlives = true
+		return nil
+	}
+}
+
+// WithExtraParams makes every request carry the parameters v, replacing
+// parameters of the same name, e.g. to pass a flag the DMWeb API
+// introduced before this package models it. See ContextWithExtraParams
+// for a single call. The authentication parameters t2maccount,
+// t2musername, t2mpassword, t2mtoken and t2mdevid are reserved and
+// can't be overridden.
+func WithExtraParams(v url.Values) Option {
+	return func(c *Client) error {
+		if err := checkExtraParams(v); err != nil {
+			return err
+		}
+		if c.extraParams == nil {
+			c.extraParams = url.Values{}
+		}
+		for k, vs := range v {
+			c.extraParams[k] = append([]string(nil), vs...)
+		}
+		return nil
+	}
+}
+
+// WithDefaultParams makes requests to the endpoint name (one of the
+// Endpoint constants) carry the parameters v unless the call sets
+// parameters of the same name, e.g. to always request getdata with
+// fullConfig and a fixed limit. Calls with several options for the same
+// endpoint merge their parameters. Like with WithExtraParams, the
+// authentication parameters are reserved.
+func WithDefaultParams(name string, v url.Values) Option {
+	return func(c *Client) error {
+		if name == "" {
+			return errors.New("endpoint name must not be empty")
+		}
+		if err := checkExtraParams(v); err != nil {
+			return err
+		}
+		if c.defaultParams == nil {
+			c.defaultParams = make(map[string]url.Values)
+		}
+		if c.defaultParams[name] == nil {
+			c.defaultParams[name] = url.Values{}
+		}
+		for k, vs := range v {
+			c.defaultParams[name][k] = append([]string(nil), vs...)
+		}
+		return nil
+	}
+}
+
+// WithMaxResponseSize bounds the size of response bodies, after
+// decompression, to n bytes instead of DefaultMaxResponseSize. Reading a
+// larger body fails with ErrResponseTooLarge, protecting long-running
+// services from running out of memory on unexpected payloads.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return errors.New("max response size must be positive")
+		}
+		c.maxResponseSize = n
+		return nil
+	}
+}
+
+// WithDryRun makes the client log the requests it would send, with the
+// credentials redacted, at debug level instead of sending them, see
+// WithLogger. Every request then succeeds with an empty but valid
+// response for its endpoint, e.g. no eWONs and no data, so the calling
+// code can be exercised without reaching Talk2M.
+func WithDryRun() Option {
+	return func(c *Client) error {
+		c.dryRun = true
+		return nil
+	}
+}
+
+// WithClock makes the client read the current time from now instead of
+// time.Now, e.g. to test time-dependent behavior like caching and
+// Retry-After dates with a frozen clock. Waiting, e.g. between retries,
+// still takes real time.
+func WithClock(now func() time.Time) Option {
+	return func(c *Client) error {
+		if now == nil {
+			return errors.New("clock must not be nil")
+		}
+		c.clock = now
+		return nil
+	}
+}
+
+// WithEwonFilter makes GetEwons, GetData and SyncData, and the methods
+// built on them, drop the eWONs whose ID isn't in include, when include
+// isn't empty, or is in exclude, which takes precedence. The eWONs are
+// dropped after decoding the response, so this doesn't reduce the data
+// transferred; use the ewonId parameter of getdata for that when
+// selecting a single eWON. Syncing still advances past the data of
+// dropped eWONs. GetDataCSV, whose body isn't decoded, requires an
+// allowed EwonID instead.
+func WithEwonFilter(include, exclude []int) Option {
+	return func(c *Client) error {
+		if len(include) == 0 && len(exclude) == 0 {
+			return errors.New("eWON filter must include or exclude at least one eWON")
+		}
+		c.ewonFilter = newEwonFilter(include, exclude)
+		return nil
+	}
+}
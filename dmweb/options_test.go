@@ -0,0 +1,227 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithEndpoint(t *testing.T) {
+	c, err := New(NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "/v2/getdata", req.URL.Path)
+		return jsonResponse(200, `{"success":true,"moreDataAvailable":false,"ewons":[]}`)
+	}), "aid", "username", "password", "devid", WithEndpoint(EndpointGetData, "v2/getdata"))
+	assert.NoError(t, err)
+
+	d, err := c.GetData(nil)
+	assert.NoError(t, err)
+	assert.True(t, d.Success)
+
+	_, err = New(nil, "aid", "username", "password", "devid", WithEndpoint(EndpointGetData, ""))
+	assert.Error(t, err)
+}
+
+func TestWithReadBufferSize(t *testing.T) {
+	c, err := New(&http.Client{}, "aid", "username", "password", "devid", WithReadBufferSize(512))
+	assert.NoError(t, err)
+	assert.Equal(t, 512, c.readBufferSize)
+
+	_, err = New(&http.Client{}, "aid", "username", "password", "devid", WithReadBufferSize(0))
+	assert.Error(t, err)
+}
+
+func TestEndpointMethod(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		switch req.URL.Path {
+		case "/getstatus":
+			assert.Equal(t, http.MethodGet, req.Method)
+			assert.Equal(t, "username", req.URL.Query().Get("t2musername"))
+			return jsonResponse(200, `{"historyCount":0,"ewonsCount":0,"ewons":[]}`)
+		case "/getdata":
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Empty(t, req.URL.RawQuery)
+			assert.NoError(t, req.ParseForm())
+			assert.Equal(t, "username", req.PostForm.Get("t2musername"))
+			assert.Len(t, req.PostForm.Get("filter"), 4096)
+			return jsonResponse(200, `{"success":true,"moreDataAvailable":false,"ewons":[]}`)
+		}
+		return jsonResponse(404, `{"success":false,"code":404,"message":"not found"}`)
+	})
+
+	_, err := c.GetStatus()
+	assert.NoError(t, err)
+	_, err = c.Request(context.Background(), EndpointGetData, url.Values{"filter": {strings.Repeat("x", 4096)}})
+	assert.NoError(t, err)
+
+	// Explicitly configured methods win over the default.
+	assert.NoError(t, WithEndpointMethod(EndpointGetStatus, http.MethodPost)(c))
+	assert.Equal(t, http.MethodPost, c.method(EndpointGetStatus, nil))
+	assert.Error(t, WithEndpointMethod(EndpointGetStatus, http.MethodDelete)(c))
+}
+
+func TestWithHTTPClientTimeout(t *testing.T) {
+	c, err := New(nil, "aid", "username", "password", "devid")
+	assert.NoError(t, err)
+	if assert.NotNil(t, c.Client) {
+		assert.Equal(t, DefaultHTTPTimeout, c.Client.Timeout)
+	}
+
+	c, err = New(nil, "aid", "username", "password", "devid", WithHTTPClientTimeout(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, c.Client.Timeout)
+
+	h := &http.Client{}
+	c, err = New(h, "aid", "username", "password", "devid", WithHTTPClientTimeout(time.Minute))
+	assert.NoError(t, err)
+	assert.Same(t, h, c.Client)
+	assert.Equal(t, time.Duration(0), h.Timeout)
+}
+
+func TestWithBaseURL(t *testing.T) {
+	for _, u := range []string{"https://staging.talk2m.com/", "http://localhost:8080/dmweb/"} {
+		c, err := New(nil, "aid", "username", "password", "devid", WithBaseURL(u))
+		if assert.NoError(t, err) {
+			assert.Equal(t, u, c.baseURL)
+		}
+	}
+	for _, u := range []string{"", "staging.talk2m.com/", "/dmweb/", "https://staging.talk2m.com", "https://staging.talk2m.com/?a=b", "://"} {
+		_, err := New(nil, "aid", "username", "password", "devid", WithBaseURL(u))
+		assert.Error(t, err, u)
+	}
+}
+
+func TestWithUserAgent(t *testing.T) {
+	c, err := New(NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "go-ewon/dmweb 0.1 my-app/1.2", req.Header.Get("User-Agent"))
+		return jsonResponse(200, `{"historyCount":0,"ewonsCount":0,"ewons":[]}`)
+	}), "aid", "username", "password", "devid", WithUserAgentSuffix("my-app/1.2"))
+	assert.NoError(t, err)
+	_, err = c.GetStatus()
+	assert.NoError(t, err)
+
+	c, err = New(nil, "aid", "username", "password", "devid", WithUserAgent("my-app/1.2"))
+	assert.NoError(t, err)
+	assert.Equal(t, "my-app/1.2", c.userAgent)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	h := &http.Client{}
+	c, err := New(&http.Client{}, "aid", "username", "password", "devid", WithHTTPClient(h))
+	assert.NoError(t, err)
+	assert.Same(t, h, c.Client)
+
+	_, err = New(nil, "aid", "username", "password", "devid", WithHTTPClient(nil))
+	assert.Error(t, err)
+}
+
+func TestWithHooks(t *testing.T) {
+	var (
+		urls     []string
+		statuses []int
+		errs     []error
+	)
+	n := 0
+	c, err := New(NewTestClient(func(req *http.Request) *http.Response {
+		n++
+		if n == 1 {
+			return jsonResponse(503, `{"success":false,"code":503,"message":"Service unavailable"}`)
+		}
+		return jsonResponse(200, `{"historyCount":0,"ewonsCount":0,"ewons":[]}`)
+	}), "aid", "username", "password", "devid",
+		WithRetry(1, time.Millisecond),
+		WithRequestHook(func(req *http.Request) {
+			urls = append(urls, req.URL.Path)
+		}),
+		WithResponseHook(func(res *http.Response, d time.Duration, err error) {
+			statuses = append(statuses, res.StatusCode)
+			errs = append(errs, err)
+			assert.True(t, d >= 0)
+		}))
+	assert.NoError(t, err)
+	_, err = c.GetStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/getstatus", "/getstatus"}, urls)
+	assert.Equal(t, []int{503, 200}, statuses)
+	if assert.Len(t, errs, 2) {
+		assert.EqualError(t, errs[0], "Service unavailable")
+		assert.NoError(t, errs[1])
+	}
+
+	_, err = New(nil, "aid", "username", "password", "devid", WithRequestHook(nil))
+	assert.Error(t, err)
+	_, err = New(nil, "aid", "username", "password", "devid", WithResponseHook(nil))
+	assert.Error(t, err)
+}
+
+func TestWithStrictValidation(t *testing.T) {
+	const devID = "6f1a2b3c-4d5e-6f70-8192-a3b4c5d6e7f8"
+	_, err := New(nil, "aid", "username", "password", "devid")
+	assert.NoError(t, err)
+	_, err = New(nil, "aid", "username", "password", devID, WithStrictValidation())
+	assert.NoError(t, err)
+	_, err = NewWithToken(nil, "aid", "token", devID, WithStrictValidation())
+	assert.NoError(t, err)
+
+	_, err = New(nil, "aid", "username", "password", "devid", WithStrictValidation())
+	assert.True(t, errors.Is(err, ErrInvalidCredentials))
+	assert.EqualError(t, err, `invalid credentials: developerID "devid" is not a UUID`)
+	_, err = New(nil, "my account\n", "username", "password", devID, WithStrictValidation())
+	assert.True(t, errors.Is(err, ErrInvalidCredentials))
+	assert.False(t, errors.Is(err, ErrMissingCredentials))
+}
+
+func TestWithTimeouts(t *testing.T) {
+	hang := func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/getstatus" {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}
+		_, ok := req.Context().Deadline()
+		assert.True(t, ok)
+		return jsonResponse(200, `{"success":true,"ewons":[]}`), nil
+	}
+	c, err := New(&http.Client{Transport: transportFunc(hang)}, "aid", "username", "password", "devid",
+		WithDefaultTimeout(10*time.Millisecond),
+		WithEndpointTimeout(EndpointGetEwons, time.Minute))
+	assert.NoError(t, err)
+
+	start := time.Now()
+	_, err = c.GetStatus()
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, time.Since(start) < time.Second)
+
+	_, err = c.GetEwons()
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	c.timeouts[EndpointGetStatus] = time.Minute
+	_, err = c.GetStatusContext(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	_, err = New(nil, "aid", "username", "password", "devid", WithDefaultTimeout(0))
+	assert.Error(t, err)
+	_, err = New(nil, "aid", "username", "password", "devid", WithEndpointTimeout("", time.Second))
+	assert.Error(t, err)
+}
+
+func TestWithStrictDecoding(t *testing.T) {
+	h := NewTestClient(func(req *http.Request) *http.Response {
+		return jsonResponse(200, `{"historyCount":0,"ewonsCount":0,"ewons":[],"newField":1}`)
+	})
+	c, err := New(h, "aid", "username", "password", "devid")
+	assert.NoError(t, err)
+	_, err = c.GetStatus()
+	assert.NoError(t, err)
+
+	c, err = New(h, "aid", "username", "password", "devid", WithStrictDecoding())
+	assert.NoError(t, err)
+	_, err = c.GetStatus()
+	assert.EqualError(t, err, `json: unknown field "newField"`)
+}
@@ -0,0 +1,238 @@
+package dmweb
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// getDataParams lists the parameters accepted by the getdata endpoint
+// and how their values are checked.
+var getDataParams = map[string]func(string) error{
+	"ewonId":     validateInt,
+	"tagId":      validateInt,
+	"from":       validateTimestamp,
+	"to":         validateTimestamp,
+	"fullConfig": func(string) error { return nil },
+	"limit":      validateInt,
+	"moreData":   func(string) error { return nil },
+}
+
+func validateInt(v string) error {
+	if _, err := strconv.Atoi(v); err != nil {
+		return fmt.Errorf("%w: %q is not an integer", errorCouldNotParseArgument, v)
+	}
+	return nil
+}
+
+// formatTimestamp formats t the way the DMWeb API expects timestamps:
+// RFC3339 in UTC, to the second, e.g. "2015-07-17T17:43:36Z". Times in
+// other zones are converted to UTC. The zero time is rejected, as it is
+// almost always a mistake and makes the DataMailbox return no data.
+func formatTimestamp(t time.Time) (string, error) {
+	if t.IsZero() {
+		return "", fmt.Errorf("%w: zero timestamp", errorCouldNotParseArgument)
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+func validateTimestamp(v string) error {
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return fmt.Errorf("%w: %q is not an RFC3339 timestamp", errorCouldNotParseArgument, v)
+	}
+	return nil
+}
+
+// ValidateDataParams checks a params map meant for GetData. Keys must be
+// one of the parameters documented on GetData, with the exact casing the
+// API expects, IDs and limit must be integers and from/to must be RFC3339
+// timestamps. The error names the offending parameter and, for keys that
+// only differ in case from a known one, the expected spelling.
+func ValidateDataParams(params map[string]string) error {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		validate, ok := getDataParams[k]
+		if !ok {
+			for known := range getDataParams {
+				if strings.EqualFold(k, known) {
+					return fmt.Errorf("unknown parameter %q, did you mean %q", k, known)
+				}
+			}
+			return fmt.Errorf("unknown parameter %q", k)
+		}
+		if err := validate(params[k]); err != nil {
+			return fmt.Errorf("parameter %s: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// GetDataParams are the parameters of a getdata request, see GetData for
+// their meaning. Nil fields are left out of the request.
+type GetDataParams struct {
+	EwonID *int
+	TagID  *int
+	// TagIDs requests the history of several tags. The DMWeb API
+	// documents tagId as a single ID and has no tag-list parameter, so a
+	// getdata request is still made per tag and the responses are
+	// merged: this saves no requests over a TagID request per tag. The
+	// MoreDataID of every tag with more data available is kept in
+	// GetDataResponse.MoreDataIDs. It can't be combined with TagID, nor
+	// with MoreData, which continues a single tag.
+	TagIDs     []int
+	From       *time.Time
+	To         *time.Time
+	FullConfig bool
+	Limit      *int
+	// MoreData continues a previous request whose response had more data
+	// available, with its MoreDataID.
+	MoreData string
+	// ConfigOnly requests the configuration and current values of the
+	// eWONs and tags without any history. The DataMailbox has no flag for
+	// this, so the request is made with fullConfig and an empty window
+	// (from and to both the current time): the response then holds every
+	// eWON and tag, including tags without history, with their current
+	// Value and Quality, and empty History. It can't be combined with
+	// From, To, Limit or MoreData.
+	ConfigOnly bool
+	// raw holds the parameters of a params map for GetData that are sent
+	// as given, see parseDataParams.
+	raw url.Values
+}
+
+// configOnly returns the parameters of a ConfigOnly request made at now.
+func (p GetDataParams) configOnly(now time.Time) (GetDataParams, error) {
+	if !p.ConfigOnly {
+		return p, nil
+	}
+	if p.From != nil || p.To != nil || p.Limit != nil || p.MoreData != "" {
+		return p, errors.New("ConfigOnly can't be combined with From, To, Limit or MoreData")
+	}
+	now = now.Truncate(time.Second)
+	p.From, p.To = &now, &now
+	p.FullConfig = true
+	return p, nil
+}
+
+// errorMultipleTagIDs is returned when parameters with several TagIDs
+// are encoded into a single request.
+var errorMultipleTagIDs = errors.New("multiple tag IDs need a request per tag")
+
+// perTag splits parameters with several TagIDs into the parameters of a
+// request per tag. It returns nil for parameters of a single request.
+func (p GetDataParams) perTag() ([]GetDataParams, error) {
+	if p.TagID != nil && len(p.TagIDs) > 0 {
+		return nil, errors.New("TagID and TagIDs can't be combined")
+	}
+	if len(p.TagIDs) < 2 {
+		return nil, nil
+	}
+	if p.MoreData != "" {
+		return nil, errors.New("MoreData can't be combined with several TagIDs")
+	}
+	ps := make([]GetDataParams, len(p.TagIDs))
+	for i := range p.TagIDs {
+		ps[i] = p
+		ps[i].TagID = &p.TagIDs[i]
+		ps[i].TagIDs = nil
+	}
+	return ps, nil
+}
+
+// values encodes the parameters into the query of a getdata request.
+func (p GetDataParams) values() (url.Values, error) {
+	if p.TagID != nil && len(p.TagIDs) > 0 {
+		return nil, errors.New("TagID and TagIDs can't be combined")
+	}
+	if len(p.TagIDs) > 1 {
+		return nil, errorMultipleTagIDs
+	}
+	if len(p.TagIDs) == 1 {
+		p.TagID = &p.TagIDs[0]
+	}
+	v := url.Values{}
+	if p.From != nil {
+		from, err := formatTimestamp(*p.From)
+		if err != nil {
+			return nil, fmt.Errorf("from: %w", err)
+		}
+		v.Set("from", from)
+	}
+	if p.To != nil {
+		to, err := formatTimestamp(*p.To)
+		if err != nil {
+			return nil, fmt.Errorf("to: %w", err)
+		}
+		v.Set("to", to)
+	}
+	if p.From != nil && p.To != nil && p.From.After(*p.To) {
+		return nil, fmt.Errorf("from %s is after to %s", v.Get("from"), v.Get("to"))
+	}
+	if p.EwonID != nil {
+		v.Set("ewonId", strconv.Itoa(*p.EwonID))
+	}
+	if p.TagID != nil {
+		v.Set("tagId", strconv.Itoa(*p.TagID))
+	}
+	if p.FullConfig {
+		v.Set("fullConfig", "")
+	}
+	if p.Limit != nil {
+		v.Set("limit", strconv.Itoa(*p.Limit))
+	}
+	if p.MoreData != "" {
+		v.Set("moreData", p.MoreData)
+	}
+	for k, vs := range p.raw {
+		v[k] = vs
+	}
+	return v, nil
+}
+
+// parseDataParams converts a params map for GetData into GetDataParams.
+// The eWON and tag IDs, limit, fullConfig and moreData are typed when
+// they parse; from, to, values that don't parse and unknown parameters
+// are sent as given, so that GetData keeps forwarding any parameter, and
+// timestamps keep their offset and fractional seconds.
+func parseDataParams(params map[string]string) GetDataParams {
+	var p GetDataParams
+	setRaw := func(k, v string) {
+		if p.raw == nil {
+			p.raw = url.Values{}
+		}
+		p.raw.Set(k, v)
+	}
+	for k, v := range params {
+		switch k {
+		case "ewonId", "tagId", "limit":
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				setRaw(k, v)
+				continue
+			}
+			switch k {
+			case "ewonId":
+				p.EwonID = &i
+			case "tagId":
+				p.TagID = &i
+			case "limit":
+				p.Limit = &i
+			}
+		case "fullConfig":
+			p.FullConfig = true
+		case "moreData":
+			p.MoreData = v
+		default:
+			setRaw(k, v)
+		}
+	}
+	return p
+}
@@ -0,0 +1,143 @@
+package dmweb
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDataParams(t *testing.T) {
+	assert.NoError(t, ValidateDataParams(nil))
+	assert.NoError(t, ValidateDataParams(map[string]string{
+		"ewonId":     "508238",
+		"tagId":      "780591",
+		"from":       "2018-11-08T14:17:58Z",
+		"to":         "2018-11-09T14:17:58+01:00",
+		"fullConfig": "",
+		"limit":      "100",
+	}))
+
+	err := ValidateDataParams(map[string]string{"ewonID": "508238"})
+	assert.EqualError(t, err, `unknown parameter "ewonID", did you mean "ewonId"`)
+
+	err = ValidateDataParams(map[string]string{"foo": "bar"})
+	assert.EqualError(t, err, `unknown parameter "foo"`)
+
+	err = ValidateDataParams(map[string]string{"from": "2018-11-08 14:17:58"})
+	assert.True(t, errors.Is(err, errorCouldNotParseArgument))
+	assert.Contains(t, err.Error(), "parameter from")
+
+	err = ValidateDataParams(map[string]string{"limit": "ten"})
+	assert.True(t, errors.Is(err, errorCouldNotParseArgument))
+}
+
+func TestGetDataWithParams(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		q := req.URL.Query()
+		assert.Equal(t, "508238", q.Get("ewonId"))
+		assert.Equal(t, "780591", q.Get("tagId"))
+		assert.Equal(t, "2018-11-08T13:17:58Z", q.Get("from"))
+		assert.Equal(t, "2018-11-09T00:00:00Z", q.Get("to"))
+		assert.Equal(t, "100", q.Get("limit"))
+		_, ok := q["fullConfig"]
+		assert.True(t, ok)
+		return jsonResponse(200, `{"success":true,"moreDataAvailable":false,"ewons":[]}`)
+	})
+
+	ewonID, tagID, limit := 508238, 780591, 100
+	from := time.Date(2018, 11, 8, 14, 17, 58, 0, time.FixedZone("CET", 3600))
+	to := time.Date(2018, 11, 9, 0, 0, 0, 0, time.UTC)
+	_, err := c.GetDataWithParams(GetDataParams{
+		EwonID:     &ewonID,
+		TagID:      &tagID,
+		From:       &from,
+		To:         &to,
+		FullConfig: true,
+		Limit:      &limit,
+	})
+	assert.NoError(t, err)
+
+	// The map-based variant ends up in the same request.
+	_, err = c.GetData(map[string]string{
+		"ewonId":     "508238",
+		"tagId":      "780591",
+		"from":       "2018-11-08T13:17:58Z",
+		"to":         "2018-11-09T00:00:00Z",
+		"fullConfig": "",
+		"limit":      "100",
+	})
+	assert.NoError(t, err)
+
+	_, err = c.GetDataWithParams(GetDataParams{From: &to, To: &from})
+	assert.EqualError(t, err, "from 2018-11-09T00:00:00Z is after to 2018-11-08T13:17:58Z")
+
+	assert.NoError(t, WithStrictValidation()(c))
+	_, err = c.GetData(map[string]string{"ewonid": "508238"})
+	assert.EqualError(t, err, `unknown parameter "ewonid", did you mean "ewonId"`)
+}
+
+func TestGetDataForwardsParams(t *testing.T) {
+	var q url.Values
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		q = req.URL.Query()
+		return jsonResponse(200, `{"success":true,"moreDataAvailable":false,"ewons":[]}`)
+	})
+	_, err := c.GetData(map[string]string{
+		"ewonId": "508238",
+		"from":   "2018-11-08T14:17:58.250+01:00",
+		"to":     "08/11/2018 14:17",
+		"newOpt": "1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "508238", q.Get("ewonId"))
+	assert.Equal(t, "2018-11-08T14:17:58.250+01:00", q.Get("from"))
+	assert.Equal(t, "08/11/2018 14:17", q.Get("to"))
+	assert.Equal(t, "1", q.Get("newOpt"))
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	s, err := formatTimestamp(time.Date(2015, 7, 17, 17, 43, 36, 500, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "2015-07-17T17:43:36Z", s)
+
+	s, err = formatTimestamp(time.Date(2015, 7, 17, 19, 43, 36, 0, time.FixedZone("CEST", 2*60*60)))
+	assert.NoError(t, err)
+	assert.Equal(t, "2015-07-17T17:43:36Z", s)
+
+	_, err = formatTimestamp(time.Time{})
+	assert.True(t, errors.Is(err, errorCouldNotParseArgument))
+
+	_, err = GetDataParams{From: &time.Time{}}.values()
+	assert.EqualError(t, err, "from: could not parse argument: zero timestamp")
+}
+
+func TestGetDataConfigOnly(t *testing.T) {
+	now := time.Date(2018, 11, 8, 13, 17, 58, 500, time.UTC)
+	c, err := New(NewTestClient(func(req *http.Request) *http.Response {
+		q := req.URL.Query()
+		assert.Equal(t, "2018-11-08T13:17:58Z", q.Get("from"))
+		assert.Equal(t, "2018-11-08T13:17:58Z", q.Get("to"))
+		_, ok := q["fullConfig"]
+		assert.True(t, ok)
+		return jsonResponse(200, `{"success":true,"moreDataAvailable":false,"ewons":[{"id":1,"tags":[
+			{"id":10,"value":21.5,"quality":"good","history":[{"date":"2018-11-08T13:17:58Z","value":21.5}]},
+			{"id":11,"value":3}]}]}`)
+	}), "aid", "username", "password", "devid", WithClock(func() time.Time { return now }))
+	assert.NoError(t, err)
+
+	d, err := c.GetDataWithParams(GetDataParams{ConfigOnly: true})
+	assert.NoError(t, err)
+	if assert.Len(t, d.Ewons, 1) && assert.Len(t, d.Ewons[0].Tags, 2) {
+		assert.Equal(t, 21.5, d.Ewons[0].Tags[0].Value)
+		assert.Empty(t, d.Ewons[0].Tags[0].History)
+		assert.Equal(t, 3.0, d.Ewons[0].Tags[1].Value)
+	}
+
+	limit := 10
+	_, err = c.GetDataWithParams(GetDataParams{ConfigOnly: true, Limit: &limit})
+	assert.EqualError(t, err, "ConfigOnly can't be combined with From, To, Limit or MoreData")
+}
@@ -0,0 +1,61 @@
+package dmweb
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// writeJSONFile writes v to the file at path as indented JSON, creating
+// or truncating it.
+func writeJSONFile(path string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// readJSONFile decodes the JSON in the file at path into v.
+func readJSONFile(path string, v interface{}) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// MarshalJSONFile writes the response to the file at path as JSON, in the
+// format of the syncdata endpoint, so it can be reloaded with
+// LoadSyncResponse. Timestamps are written in RFC 3339 with their offset,
+// so they reload as the same instants.
+func (s *SyncResponse) MarshalJSONFile(path string) error {
+	return writeJSONFile(path, s)
+}
+
+// LoadSyncResponse reads a response written by MarshalJSONFile, or
+// captured from the syncdata endpoint, from the file at path.
+func LoadSyncResponse(path string) (*SyncResponse, error) {
+	var s SyncResponse
+	if err := readJSONFile(path, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// MarshalJSONFile writes the response to the file at path as JSON, in the
+// format of the getdata endpoint, so it can be reloaded with
+// LoadGetDataResponse. Timestamps are written in RFC 3339 with their
+// offset, so they reload as the same instants.
+func (d *GetDataResponse) MarshalJSONFile(path string) error {
+	return writeJSONFile(path, d)
+}
+
+// LoadGetDataResponse reads a response written by MarshalJSONFile, or
+// captured from the getdata endpoint, from the file at path.
+func LoadGetDataResponse(path string) (*GetDataResponse, error) {
+	var d GetDataResponse
+	if err := readJSONFile(path, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
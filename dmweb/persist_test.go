@@ -0,0 +1,48 @@
+package dmweb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONFileRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dmweb")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	date := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	ewons := []DataEwon{{
+		ID:              1,
+		Name:            "Ewon1",
+		LastSynchroDate: date,
+		Tags: []DataTag{{
+			ID:      2,
+			Name:    "Temp",
+			History: []HistoryPoint{{Date: date, Value: 1.5, Quality: "good"}},
+		}},
+	}}
+
+	s := &SyncResponse{Success: true, TransactionID: "42", MoreDataAvailable: true, Ewons: ewons}
+	path := filepath.Join(dir, "sync.json")
+	assert.NoError(t, s.MarshalJSONFile(path))
+	loaded, err := LoadSyncResponse(path)
+	assert.NoError(t, err)
+	assert.Equal(t, s, loaded)
+
+	d := &GetDataResponse{Success: true, Ewons: ewons}
+	path = filepath.Join(dir, "data.json")
+	assert.NoError(t, d.MarshalJSONFile(path))
+	loadedData, err := LoadGetDataResponse(path)
+	assert.NoError(t, err)
+	assert.Equal(t, d, loadedData)
+
+	_, err = LoadSyncResponse(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// maxPollBackoff caps the backoff of PollSyncFrom after repeated errors, as
+// a power of two multiple of the interval.
+const maxPollBackoff = 5
+
+// PollSync syncs the data of the account every interval until ctx is
+// done, passing every page to handler. It keeps the transaction ID in
+// memory, so it starts with the oldest data in the DataMailbox; use
+// PollSyncFrom to resume across restarts.
+func (c *Client) PollSync(ctx context.Context, interval time.Duration, handler func(*SyncResponse) error) error {
+	return c.PollSyncFrom(ctx, &MemoryCursorStore{}, interval, handler)
+}
+
+// PollSyncFrom is like PollSync, resuming from the transaction ID saved
+// in store and saving the transaction ID of every page once handler
+// processed it, with a Syncer. Every round syncs the data that arrived
+// since the previous one, and pages with more data available are synced
+// right away. The first round starts after a random delay of up to a
+// tenth of interval, and every interval is jittered by up to a tenth, so
+// that many instances started together don't poll in step.
+// A failed round, including a handler error, is logged and retried
+// after a backoff doubling with every consecutive failure, up to 32
+// times interval; the page the handler failed on is synced again. Auth
+// errors, see IsAuthError, aren't retried and are returned. Otherwise
+// PollSyncFrom returns the error of ctx once it is done.
+func (c *Client) PollSyncFrom(ctx context.Context, store CursorStore, interval time.Duration, handler func(*SyncResponse) error) error {
+	if interval <= 0 {
+		return errors.New("poll interval must be positive")
+	}
+	s := NewSyncer(c, store)
+	delay := time.Duration(rand.Int63n(int64(interval/10) + 1))
+	failures := 0
+	for {
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+		err := pollRound(ctx, s, handler)
+		switch {
+		case err == nil:
+			failures = 0
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case IsAuthError(err):
+			return err
+		default:
+			if failures < maxPollBackoff {
+				failures++
+			}
+		}
+		delay = jitter(interval << uint(failures))
+		if err != nil {
+			c.log().Warnf("dmweb: sync failed, retrying in %s: %v", delay, err)
+		}
+	}
+}
+
+// pollRound syncs pages until no more data is available.
+func pollRound(ctx context.Context, s *Syncer, handler func(*SyncResponse) error) error {
+	for {
+		r, err := s.Next(ctx, handler)
+		if err != nil {
+			return err
+		}
+		if !r.MoreDataAvailable {
+			return nil
+		}
+	}
+}
+
+// jitter returns d shifted randomly by up to a tenth of d, up or down.
+func jitter(d time.Duration) time.Duration {
+	j := d / 10
+	return d - j + time.Duration(rand.Int63n(int64(2*j)+1))
+}
@@ -0,0 +1,94 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollSync(t *testing.T) {
+	var mu sync.Mutex
+	var lastIDs []string
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		lastIDs = append(lastIDs, req.URL.Query().Get("lastTransactionId"))
+		switch len(lastIDs) {
+		case 1:
+			return jsonResponse(200, `{"success":true,"transactionId":"1","moreDataAvailable":true,"ewons":[]}`)
+		case 3:
+			return jsonResponse(503, `{"success":false,"code":503,"message":"unavailable"}`)
+		}
+		return jsonResponse(200, `{"success":true,"transactionId":"2","ewons":[]}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var pages []string
+	handlerFailed := false
+	err := c.PollSync(ctx, time.Millisecond, func(s *SyncResponse) error {
+		if s.TransactionID == "2" && !handlerFailed {
+			handlerFailed = true
+			return errors.New("handler failed")
+		}
+		pages = append(pages, s.TransactionID)
+		if len(pages) == 3 {
+			cancel()
+		}
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, []string{"1", "2", "2"}, pages)
+	mu.Lock()
+	defer mu.Unlock()
+	// The page the handler failed on and the failed request are synced
+	// again from the same transaction.
+	assert.Equal(t, []string{"", "1", "1", "1", "2"}, lastIDs[:5])
+}
+
+func TestPollSyncAuthError(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(401, `{"success":false,"code":401,"message":"Invalid credentials"}`)
+	})
+	err := c.PollSync(context.Background(), time.Millisecond, func(*SyncResponse) error { return nil })
+	assert.True(t, IsAuthError(err))
+
+	assert.Error(t, c.PollSync(context.Background(), 0, nil))
+}
+
+func TestJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		d := jitter(time.Second)
+		assert.True(t, d >= 900*time.Millisecond && d <= 1100*time.Millisecond, d)
+	}
+}
+
+func TestPollSyncFrom(t *testing.T) {
+	var mu sync.Mutex
+	var lastIDs []string
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		lastIDs = append(lastIDs, req.URL.Query().Get("lastTransactionId"))
+		return jsonResponse(200, `{"success":true,"transactionId":"8","ewons":[]}`)
+	})
+	store := &MemoryCursorStore{}
+	assert.NoError(t, store.Save("7"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := c.PollSyncFrom(ctx, store, time.Millisecond, func(s *SyncResponse) error {
+		cancel()
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	id, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "8", id)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"7"}, lastIDs)
+}
@@ -0,0 +1,118 @@
+package dmweb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// latestRecords reduces records to the newest record of each tag of
+// each eWON.
+func latestRecords(records []Record) []Record {
+	latest := make(map[tagKey]int)
+	var out []Record
+	for _, r := range records {
+		k := tagKey{r.EwonID, r.TagID}
+		i, ok := latest[k]
+		if !ok {
+			latest[k] = len(out)
+			out = append(out, r)
+			continue
+		}
+		if !r.Date.Before(out[i].Date) {
+			out[i] = r
+		}
+	}
+	return out
+}
+
+// promMetricName turns a tag name into a valid Prometheus metric name by
+// replacing every character outside [a-zA-Z0-9_] with an underscore and
+// prefixing names that start with a digit.
+func promMetricName(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			b[i] = '_'
+		}
+	}
+	if len(b) == 0 || b[0] >= '0' && b[0] <= '9' {
+		return "_" + string(b)
+	}
+	return string(b)
+}
+
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WritePrometheus writes the latest value of every tag in records to w
+// in the Prometheus text exposition format, e.g.
+//
+//	# TYPE TAG_2 gauge
+//	TAG_2{ewon="ltn_flexy",ewon_id="508238",tag="TAG_2"} 1510
+//
+// The metric name is the sanitized tag name. Tags whose names only
+// differ in characters that get sanitized away, like "a-b" and "a_b",
+// share a metric name; their series stay distinct through the tag label,
+// which always holds the original tag name.
+// Samples carry no timestamp, as the Pushgateway rejects pushed samples
+// with one; use WritePrometheusWithTimestamps to include the record dates.
+func WritePrometheus(w io.Writer, records []Record) error {
+	return writePrometheus(w, records, false)
+}
+
+// WritePrometheusWithTimestamps is like WritePrometheus, but ends every
+// sample with the date of the record in milliseconds since the epoch, e.g.
+//
+//	TAG_2{ewon="ltn_flexy",ewon_id="508238",tag="TAG_2"} 1510 1541686686000
+func WritePrometheusWithTimestamps(w io.Writer, records []Record) error {
+	return writePrometheus(w, records, true)
+}
+
+func writePrometheus(w io.Writer, records []Record, timestamps bool) error {
+	type sample struct {
+		name string
+		r    Record
+	}
+	latest := latestRecords(records)
+	samples := make([]sample, len(latest))
+	for i, r := range latest {
+		samples[i] = sample{promMetricName(r.TagName), r}
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		a, b := samples[i], samples[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if a.r.EwonName != b.r.EwonName {
+			return a.r.EwonName < b.r.EwonName
+		}
+		if a.r.EwonID != b.r.EwonID {
+			return a.r.EwonID < b.r.EwonID
+		}
+		return a.r.TagName < b.r.TagName
+	})
+
+	var buf bytes.Buffer
+	for i, s := range samples {
+		if i == 0 || samples[i-1].name != s.name {
+			fmt.Fprintf(&buf, "# TYPE %s gauge\n", s.name)
+		}
+		fmt.Fprintf(&buf, "%s{ewon=\"%s\",ewon_id=\"%d\",tag=\"%s\"} %s",
+			s.name,
+			promLabelEscaper.Replace(s.r.EwonName),
+			s.r.EwonID,
+			promLabelEscaper.Replace(s.r.TagName),
+			strconv.FormatFloat(s.r.Value, 'g', -1, 64),
+		)
+		if timestamps {
+			fmt.Fprintf(&buf, " %d", s.r.Date.UnixNano()/int64(time.Millisecond))
+		}
+		buf.WriteByte('\n')
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
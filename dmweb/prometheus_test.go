@@ -0,0 +1,42 @@
+package dmweb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPrometheusRecords() []Record {
+	t1, _ := time.Parse(time.RFC3339, "2018-11-08T14:17:58Z")
+	t2, _ := time.Parse(time.RFC3339, "2018-11-08T14:18:06Z")
+	return []Record{
+		{EwonID: 508238, EwonName: "ltn_flexy", TagID: 1, TagName: "TAG_2", Date: t1, Value: 1},
+		{EwonID: 508238, EwonName: "ltn_flexy", TagID: 1, TagName: "TAG_2", Date: t2, Value: 1510.5},
+		{EwonID: 508238, EwonName: "ltn_flexy", TagID: 2, TagName: "2nd-tag", Date: t1, Value: 3},
+		{EwonID: 508239, EwonName: `say "hi"`, TagID: 3, TagName: "TAG_2", Date: t1, Value: 4},
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WritePrometheus(&buf, testPrometheusRecords()))
+	assert.Equal(t, `# TYPE TAG_2 gauge
+TAG_2{ewon="ltn_flexy",ewon_id="508238",tag="TAG_2"} 1510.5
+TAG_2{ewon="say \"hi\"",ewon_id="508239",tag="TAG_2"} 4
+# TYPE _2nd_tag gauge
+_2nd_tag{ewon="ltn_flexy",ewon_id="508238",tag="2nd-tag"} 3
+`, buf.String())
+}
+
+func TestWritePrometheusWithTimestamps(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WritePrometheusWithTimestamps(&buf, testPrometheusRecords()))
+	assert.Equal(t, `# TYPE TAG_2 gauge
+TAG_2{ewon="ltn_flexy",ewon_id="508238",tag="TAG_2"} 1510.5 1541686686000
+TAG_2{ewon="say \"hi\"",ewon_id="508239",tag="TAG_2"} 4 1541686678000
+# TYPE _2nd_tag gauge
+_2nd_tag{ewon="ltn_flexy",ewon_id="508238",tag="2nd-tag"} 3 1541686678000
+`, buf.String())
+}
@@ -0,0 +1,76 @@
+package dmweb
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Quality is the quality of a logged value.
+type Quality string
+
+// Qualities of logged values. The eWON logs the first value after a
+// (re)start with QualityInitialGood.
+const (
+	QualityGood        Quality = "good"
+	QualityInitialGood Quality = "initialGood"
+	QualityBad         Quality = "bad"
+	QualityUncertain   Quality = "uncertain"
+)
+
+// qualities maps the lowercased known qualities to their constants.
+var qualities = map[string]Quality{
+	"good":        QualityGood,
+	"initialgood": QualityInitialGood,
+	"bad":         QualityBad,
+	"uncertain":   QualityUncertain,
+}
+
+// UnmarshalJSON decodes a quality, normalizing the spelling of known
+// qualities. Unknown qualities, like the detailed OPC qualities some
+// devices report, e.g. "badNotConnected", are kept as is.
+func (q *Quality) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if k, ok := qualities[strings.ToLower(s)]; ok {
+		*q = k
+		return nil
+	}
+	*q = Quality(s)
+	return nil
+}
+
+// IsGood reports whether the value is good, which includes
+// QualityInitialGood and detailed qualities starting with "good".
+func (q Quality) IsGood() bool {
+	return q == QualityInitialGood || hasPrefixFold(string(q), string(QualityGood))
+}
+
+// IsBad reports whether the value is bad, which includes detailed
+// qualities starting with "bad".
+func (q Quality) IsBad() bool {
+	return hasPrefixFold(string(q), string(QualityBad))
+}
+
+// IsUncertain reports whether the value is uncertain, which includes
+// detailed qualities starting with "uncertain".
+func (q Quality) IsUncertain() bool {
+	return hasPrefixFold(string(q), string(QualityUncertain))
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// GoodPoints returns the points of good quality, see Quality.IsGood, in
+// order. Points without a quality are dropped as well.
+func GoodPoints(points []HistoryPoint) []HistoryPoint {
+	var out []HistoryPoint
+	for _, p := range points {
+		if p.Quality.IsGood() {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package dmweb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuality(t *testing.T) {
+	var ps []HistoryPoint
+	err := json.Unmarshal([]byte(`[
+		{"quality":"good"},
+		{"quality":"InitialGood"},
+		{"quality":"bad"},
+		{"quality":"badNotConnected"},
+		{"quality":"uncertainLastUsable"}
+	]`), &ps)
+	assert.NoError(t, err)
+	var got []Quality
+	for _, p := range ps {
+		got = append(got, p.Quality)
+	}
+	assert.Equal(t, []Quality{QualityGood, QualityInitialGood, QualityBad, "badNotConnected", "uncertainLastUsable"}, got)
+
+	assert.True(t, QualityGood.IsGood())
+	assert.True(t, QualityInitialGood.IsGood())
+	assert.False(t, QualityBad.IsGood())
+	assert.True(t, Quality("badNotConnected").IsBad())
+	assert.True(t, Quality("uncertainLastUsable").IsUncertain())
+	assert.False(t, Quality("").IsGood())
+}
+
+func TestGoodPoints(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ps := []HistoryPoint{
+		{Date: t0, Value: 1, Quality: QualityInitialGood},
+		{Date: t0.Add(time.Minute), Value: 2, Quality: QualityBad},
+		{Date: t0.Add(2 * time.Minute), Value: 3, Quality: QualityGood},
+	}
+	assert.Equal(t, []HistoryPoint{ps[0], ps[2]}, GoodPoints(ps))
+}
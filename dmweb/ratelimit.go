@@ -0,0 +1,41 @@
+package dmweb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter returns the limiter pacing the requests of the client, as
+// configured with WithRateLimit, or nil if requests aren't paced. Its
+// Tokens, Limit and Burst methods expose the state of the limiter for
+// metrics; changing its limit affects all subsequent requests.
+func (c *Client) RateLimiter() *rate.Limiter {
+	return c.limiter
+}
+
+// waitRateLimit blocks until the rate limiter allows a request. When ctx
+// is done, or its deadline would pass before a token is available, the
+// returned error wraps the context's error.
+func (c *Client) waitRateLimit(ctx context.Context, endpoint string) error {
+	if c.limiter == nil {
+		return nil
+	}
+	start := c.now()
+	err := c.limiter.Wait(ctx)
+	if err == nil {
+		if d := c.since(start); d >= time.Millisecond {
+			c.log().Debugf("dmweb: %s waited %s for the rate limit", endpoint, d)
+		}
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return fmt.Errorf("%w: %v", context.DeadlineExceeded, err)
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimit(t *testing.T) {
+	n := 0
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		n++
+		return jsonResponse(200, `{"historyCount":1,"ewonsCount":0,"ewons":[]}`)
+	})
+	assert.Nil(t, c.RateLimiter())
+	assert.Error(t, WithRateLimit(0, 1)(c))
+	assert.Error(t, WithRateLimit(1, 0)(c))
+	assert.NoError(t, WithRateLimit(rate.Every(20*time.Millisecond), 1)(c))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := c.GetStatus()
+		assert.NoError(t, err)
+	}
+	assert.True(t, time.Since(start) >= 35*time.Millisecond)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, 1, c.RateLimiter().Burst())
+	assert.True(t, c.RateLimiter().Tokens() < 1)
+}
+
+func TestRateLimitContext(t *testing.T) {
+	n := 0
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		n++
+		return jsonResponse(200, `{"historyCount":1,"ewonsCount":0,"ewons":[]}`)
+	})
+	assert.NoError(t, WithRateLimit(rate.Every(time.Hour), 1)(c))
+	assert.NoError(t, WithRetry(3, time.Millisecond)(c))
+
+	_, err := c.GetStatus()
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.GetStatusContext(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, 1, n)
+}
@@ -0,0 +1,41 @@
+package dmweb
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+// credentialParams matches the values of the parameters that carry
+// credentials in a URL or form-encoded body.
+var credentialParams = regexp.MustCompile(`(t2mpassword|t2mtoken|t2musername)=[^&#\s"]*`)
+
+// RedactURL replaces the values of the t2mpassword, t2mtoken and
+// t2musername parameters in the URL or query string s with "***", so it
+// can be logged safely. The client redacts the URLs in the errors it
+// returns and in the requests it passes to hooks, but not in the
+// *http.Response returned by Request.
+func RedactURL(s string) string {
+	return credentialParams.ReplaceAllString(s, "${1}=***")
+}
+
+// redactError redacts the URL of err if it is a *url.Error, as returned
+// by the HTTP client.
+func redactError(err error) error {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		ue.URL = RedactURL(ue.URL)
+	}
+	return err
+}
+
+// redactRequest returns a copy of req with its URL redacted, for passing
+// to hooks.
+func redactRequest(req *http.Request) *http.Request {
+	r := req.Clone(req.Context())
+	u := *req.URL
+	u.RawQuery = RedactURL(u.RawQuery)
+	r.URL = &u
+	return r
+}
@@ -0,0 +1,44 @@
+package dmweb
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactURL(t *testing.T) {
+	assert.Equal(t,
+		"https://data.talk2m.com/getstatus?t2maccount=aid&t2mdevid=devid&t2mpassword=***&t2musername=***",
+		RedactURL("https://data.talk2m.com/getstatus?t2maccount=aid&t2mdevid=devid&t2mpassword=s%26cret&t2musername=user"))
+	assert.Equal(t, "t2mtoken=***&t2maccount=aid", RedactURL("t2mtoken=abc&t2maccount=aid"))
+	assert.Equal(t, "https://data.talk2m.com/getstatus", RedactURL("https://data.talk2m.com/getstatus"))
+}
+
+func TestRequestErrorRedacted(t *testing.T) {
+	var hooked []string
+	c := newTestDMWebClient(nil)
+	c.Password = "s3cret"
+	c.Client = &http.Client{Transport: transportFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})}
+	assert.NoError(t, WithRequestHook(func(req *http.Request) {
+		hooked = append(hooked, req.URL.String())
+	})(c))
+	assert.NoError(t, WithResponseHook(func(res *http.Response, d time.Duration, err error) {
+		hooked = append(hooked, err.Error())
+	})(c))
+
+	_, err := c.GetStatus()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "t2mpassword=***")
+		assert.NotContains(t, err.Error(), "s3cret")
+	}
+	if assert.Len(t, hooked, 2) {
+		for _, s := range hooked {
+			assert.NotContains(t, s, "s3cret")
+		}
+	}
+}
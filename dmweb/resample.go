@@ -0,0 +1,96 @@
+package dmweb
+
+import (
+	"sort"
+	"time"
+)
+
+// FillPolicy defines how Resample fills sample times without a logged
+// point.
+type FillPolicy int
+
+const (
+	// FillNone leaves sample times without a logged point out.
+	FillNone FillPolicy = iota
+	// FillForward carries the last logged value forward into sample
+	// times without a logged point.
+	FillForward
+	// FillLinear interpolates linearly between the newest point logged
+	// at or before the sample time and the oldest point logged after
+	// it, however far apart they are.
+	FillLinear
+)
+
+// Resample turns points, e.g. the history of a tag logged on change,
+// into samples evenly spaced by interval, at from, from+interval, ... up
+// to and including to.
+// The sample at time t takes the newest point logged in (t-interval, t].
+// Without such a point, FillForward uses the newest point logged at or
+// before t, while FillNone skips t. FillLinear instead takes the value
+// at t itself, interpolated between the points logged around it, and
+// skips sample times after the last point. Sample times before the first
+// point are always skipped.
+// Every sample keeps the quality of the point its value was taken from,
+// so a forward-filled sample is as good as the value it carries.
+// points don't need to be sorted.
+func Resample(points []HistoryPoint, from, to time.Time, interval time.Duration, fill FillPolicy) []HistoryPoint {
+	if interval <= 0 || to.Before(from) {
+		return nil
+	}
+	sorted := make([]HistoryPoint, len(points))
+	copy(sorted, points)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	var out []HistoryPoint
+	i := 0 // index of the first point after the current sample time
+	for t := from; !t.After(to); t = t.Add(interval) {
+		for i < len(sorted) && !sorted[i].Date.After(t) {
+			i++
+		}
+		if i == 0 {
+			continue
+		}
+		last := sorted[i-1]
+		if fill == FillLinear && last.Date.Before(t) {
+			if i == len(sorted) {
+				break
+			}
+			next := sorted[i]
+			f := float64(t.Sub(last.Date)) / float64(next.Date.Sub(last.Date))
+			v := last.Value + f*(next.Value-last.Value)
+			out = append(out, HistoryPoint{Date: t, DataType: last.DataType, Value: v, Quality: last.Quality})
+			continue
+		}
+		if fill == FillNone && !last.Date.After(t.Add(-interval)) {
+			continue
+		}
+		out = append(out, HistoryPoint{Date: t, DataType: last.DataType, Value: last.Value, Quality: last.Quality})
+	}
+	return out
+}
+
+// ResampleEvery is like Resample, with the sample times the multiples of
+// interval, counted from the zero time in UTC, from the first to the last
+// point, e.g. every full minute. Points logged before the first sample
+// time, in a leading partial interval, count towards the first sample;
+// points logged after the last sample time, in a trailing partial
+// interval, are left out, as that interval isn't complete yet.
+func ResampleEvery(points []HistoryPoint, interval time.Duration, fill FillPolicy) []HistoryPoint {
+	if interval <= 0 || len(points) == 0 {
+		return nil
+	}
+	first, last := points[0].Date, points[0].Date
+	for _, p := range points[1:] {
+		if p.Date.Before(first) {
+			first = p.Date
+		}
+		if p.Date.After(last) {
+			last = p.Date
+		}
+	}
+	from := first.Truncate(interval)
+	if from.Before(first) {
+		from = from.Add(interval)
+	}
+	return Resample(points, from, last.Truncate(interval), interval, fill)
+}
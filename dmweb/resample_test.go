@@ -0,0 +1,80 @@
+package dmweb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResample(t *testing.T) {
+	t0, _ := time.Parse(time.RFC3339, "2018-11-08T14:00:00Z")
+	points := []HistoryPoint{
+		{Date: t0.Add(90 * time.Second), Value: 2, Quality: "good"},
+		{Date: t0.Add(10 * time.Second), Value: 1, Quality: "initialGood"},
+		{Date: t0.Add(5 * time.Minute), Value: 3, Quality: "good"},
+	}
+
+	// Forward-fill carries 2 across the gap between 1m30s and 5m.
+	got := Resample(points, t0, t0.Add(5*time.Minute), time.Minute, FillForward)
+	assert.Equal(t, []HistoryPoint{
+		{Date: t0.Add(1 * time.Minute), Value: 1, Quality: "initialGood"},
+		{Date: t0.Add(2 * time.Minute), Value: 2, Quality: "good"},
+		{Date: t0.Add(3 * time.Minute), Value: 2, Quality: "good"},
+		{Date: t0.Add(4 * time.Minute), Value: 2, Quality: "good"},
+		{Date: t0.Add(5 * time.Minute), Value: 3, Quality: "good"},
+	}, got)
+
+	got = Resample(points, t0, t0.Add(5*time.Minute), time.Minute, FillNone)
+	assert.Equal(t, []HistoryPoint{
+		{Date: t0.Add(1 * time.Minute), Value: 1, Quality: "initialGood"},
+		{Date: t0.Add(2 * time.Minute), Value: 2, Quality: "good"},
+		{Date: t0.Add(5 * time.Minute), Value: 3, Quality: "good"},
+	}, got)
+
+	assert.Nil(t, Resample(points, t0, t0.Add(time.Hour), 0, FillForward))
+}
+
+func TestResampleLinear(t *testing.T) {
+	t0, _ := time.Parse(time.RFC3339, "2018-11-08T14:00:00Z")
+	points := []HistoryPoint{
+		{Date: t0.Add(30 * time.Second), Value: 0, Quality: "good"},
+		{Date: t0.Add(2 * time.Minute), Value: 6, Quality: "good"},
+		{Date: t0.Add(4*time.Minute + 30*time.Second), Value: 1, Quality: "good"},
+	}
+
+	// The gap between 2m and 4m30s is interpolated, the samples before
+	// the first and after the last point are skipped.
+	got := Resample(points, t0, t0.Add(6*time.Minute), time.Minute, FillLinear)
+	assert.Equal(t, []HistoryPoint{
+		{Date: t0.Add(1 * time.Minute), Value: 2, Quality: "good"},
+		{Date: t0.Add(2 * time.Minute), Value: 6, Quality: "good"},
+		{Date: t0.Add(3 * time.Minute), Value: 4, Quality: "good"},
+		{Date: t0.Add(4 * time.Minute), Value: 2, Quality: "good"},
+	}, got)
+}
+
+func TestResampleEvery(t *testing.T) {
+	t0, _ := time.Parse(time.RFC3339, "2018-11-08T14:00:00Z")
+	points := []HistoryPoint{
+		{Date: t0.Add(10 * time.Second), Value: 1},
+		{Date: t0.Add(50 * time.Second), Value: 2},
+		{Date: t0.Add(150 * time.Second), Value: 3},
+		{Date: t0.Add(190 * time.Second), Value: 4},
+	}
+
+	// The leading partial minute counts towards 14:01, the trailing
+	// partial minute after 14:03 is left out.
+	got := ResampleEvery(points, time.Minute, FillNone)
+	assert.Equal(t, []HistoryPoint{
+		{Date: t0.Add(1 * time.Minute), Value: 2},
+		{Date: t0.Add(3 * time.Minute), Value: 3},
+	}, got)
+
+	got = ResampleEvery(points, time.Minute, FillForward)
+	assert.Len(t, got, 3)
+	assert.Equal(t, 2.0, got[1].Value)
+
+	assert.Nil(t, ResampleEvery(nil, time.Minute, FillForward))
+	assert.Nil(t, ResampleEvery(points, 0, FillForward))
+}
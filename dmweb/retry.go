@@ -0,0 +1,102 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryPolicy defines how failed requests are retried. The zero value
+// doesn't retry.
+type retryPolicy struct {
+	maxRetries int
+	baseDelay  time.Duration
+}
+
+// maxRetryDelay caps the delay between attempts, before jitter, unless
+// the server asks for a longer one with a Retry-After header.
+const maxRetryDelay = 30 * time.Second
+
+// retryableStatus lists the statuses that signal a transient failure.
+var retryableStatus = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// next returns how long to wait before retrying the failed attempt, and
+// whether to retry at all. Only GET requests are retried, on connection
+// errors and retryable statuses, but not during maintenance.
+// The delay doubles with every attempt, up to maxRetryDelay or the base
+// delay if longer, with jitter, unless the response carries a Retry-After
+// header, which is relative to now.
+func (p retryPolicy) next(method string, attempt int, res *http.Response, err error, now time.Time) (time.Duration, bool) {
+	if attempt >= p.maxRetries || method != http.MethodGet {
+		return 0, false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+	if errors.Is(err, ErrServiceUnavailableMaintenance) {
+		return 0, false
+	}
+	if res != nil {
+		if !retryableStatus[res.StatusCode] {
+			return 0, false
+		}
+		if d, ok := retryAfter(res.Header, now); ok {
+			return d, true
+		}
+	}
+	d := maxRetryDelay
+	if p.baseDelay > d {
+		d = p.baseDelay
+	}
+	if attempt < 32 && p.baseDelay < d>>uint(attempt) {
+		d = p.baseDelay << uint(attempt)
+	}
+	if d <= 0 {
+		return 0, true
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1)), true
+}
+
+// retryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func retryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// RetryError is returned by a request that failed after being retried.
+// It holds the error of every attempt, in order. When the context was
+// cancelled while waiting to retry, the last error is the context's.
+type RetryError struct {
+	Errors []error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("request failed after %d attempts: %v", len(e.Errors), e.Errors[len(e.Errors)-1])
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *RetryError) Unwrap() error {
+	return e.Errors[len(e.Errors)-1]
+}
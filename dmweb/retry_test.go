@@ -0,0 +1,109 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	n := 0
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		n++
+		if n < 3 {
+			return jsonResponse(503, `{"success":false,"code":503,"message":"Service unavailable"}`)
+		}
+		return jsonResponse(200, `{"historyCount":1,"ewonsCount":0,"ewons":[]}`)
+	})
+	assert.NoError(t, WithRetry(3, time.Millisecond)(c))
+
+	s, err := c.GetStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, s.HistoryCount)
+	assert.Equal(t, 3, n)
+}
+
+func TestRetryExhausted(t *testing.T) {
+	n := 0
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		n++
+		return jsonResponse(502, `{"success":false,"code":502,"message":"Bad gateway"}`)
+	})
+	assert.NoError(t, WithRetry(2, time.Millisecond)(c))
+
+	_, err := c.GetStatus()
+	assert.Equal(t, 3, n)
+	var re *RetryError
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Len(t, re.Errors, 3)
+	}
+	assert.EqualError(t, err, "request failed after 3 attempts: Bad gateway")
+}
+
+func TestRetryNotOnAuthError(t *testing.T) {
+	n := 0
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		n++
+		return jsonResponse(401, `{"success":false,"code":401,"message":"Invalid credentials"}`)
+	})
+	assert.NoError(t, WithRetry(3, time.Millisecond)(c))
+
+	_, err := c.GetStatus()
+	assert.Equal(t, 1, n)
+	assert.EqualError(t, err, "Invalid credentials")
+}
+
+func TestRetryContextCancelled(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(503, `{"success":false,"code":503,"message":"Service unavailable"}`)
+	})
+	assert.NoError(t, WithRetry(3, time.Hour)(c))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := c.GetStatusContext(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2018, 11, 8, 14, 17, 58, 0, time.UTC)
+	h := http.Header{}
+	_, ok := retryAfter(h, now)
+	assert.False(t, ok)
+
+	h.Set("Retry-After", "2")
+	d, ok := retryAfter(h, now)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, d)
+
+	h.Set("Retry-After", now.Add(time.Minute).Format(http.TimeFormat))
+	d, ok = retryAfter(h, now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, d)
+
+	p := retryPolicy{maxRetries: 1, baseDelay: time.Hour}
+	d, ok = p.next(http.MethodGet, 0, &http.Response{StatusCode: 429, Header: http.Header{"Retry-After": {"1"}}}, errors.New("slow down"), time.Now())
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, d)
+	_, ok = p.next(http.MethodPost, 0, &http.Response{StatusCode: 503}, errors.New("unavailable"), time.Now())
+	assert.False(t, ok)
+}
+
+func TestRetryDelayCapped(t *testing.T) {
+	p := retryPolicy{maxRetries: 1000, baseDelay: time.Second}
+	err := errors.New("connection reset")
+	for _, attempt := range []int{0, 5, 34, 63, 64, 999} {
+		d, ok := p.next(http.MethodGet, attempt, nil, err, time.Now())
+		assert.True(t, ok)
+		assert.True(t, d > 0 && d <= maxRetryDelay, "attempt %d: %s", attempt, d)
+	}
+
+	p.baseDelay = time.Minute
+	d, ok := p.next(http.MethodGet, 100, nil, err, time.Now())
+	assert.True(t, ok)
+	assert.True(t, d >= 30*time.Second && d <= time.Minute, d)
+}
@@ -0,0 +1,107 @@
+package dmweb
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"net/http"
+)
+
+// ByID returns the status of the eWON with the given ID.
+func (s *GetStatusResponse) ByID(id int) (*EwonStatus, bool) {
+	for i := range s.Ewons {
+		if s.Ewons[i].ID == id {
+			return &s.Ewons[i], true
+		}
+	}
+	return nil, false
+}
+
+// ByName returns the status of the first eWON with the given name.
+func (s *GetStatusResponse) ByName(name string) (*EwonStatus, bool) {
+	for i := range s.Ewons {
+		if s.Ewons[i].Name == name {
+			return &s.Ewons[i], true
+		}
+	}
+	return nil, false
+}
+
+// TotalHistoryCount returns the sum of the history counts of the eWONs
+// in the response. It normally equals HistoryCount, the count the
+// DataMailbox reports for the whole account.
+func (s *GetStatusResponse) TotalHistoryCount() int {
+	n := 0
+	for _, e := range s.Ewons {
+		n += e.HistoryCount
+	}
+	return n
+}
+
+// statusEntry is the previous status response of a client, to detect
+// unmodified statuses.
+type statusEntry struct {
+	etag         string
+	lastModified string
+	sum          [sha256.Size]byte
+	status       *GetStatusResponse
+}
+
+// response returns a copy of the status, so that callers can't modify
+// the entry, marked unmodified, with the metadata m when it isn't nil.
+func (e *statusEntry) response(unmodified bool, m *ResponseMeta) *GetStatusResponse {
+	s := *e.status
+	s.Ewons = append([]EwonStatus(nil), s.Ewons...)
+	s.Unmodified = unmodified
+	if m != nil {
+		s.Meta = m
+	}
+	return &s
+}
+
+// getStatus makes a conditional getstatus request, see GetStatus.
+func (c *Client) getStatus(ctx context.Context) (*GetStatusResponse, error) {
+	c.statusMu.Lock()
+	prev := c.lastStatus
+	c.statusMu.Unlock()
+	header := http.Header{}
+	if prev != nil && prev.etag != "" {
+		header.Set("If-None-Match", prev.etag)
+	}
+	if prev != nil && prev.lastModified != "" {
+		header.Set("If-Modified-Since", prev.lastModified)
+	}
+	res, err := c.request(ctx, EndpointGetStatus, nil, header)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res.Body)
+	if res.StatusCode == http.StatusNotModified {
+		return prev.response(true, newResponseMeta(res)), nil
+	}
+	var head headBuffer
+	h := sha256.New()
+	res.Body = readCloser{Reader: io.TeeReader(res.Body, io.MultiWriter(&head, h)), Closer: res.Body}
+	s := &GetStatusResponse{Meta: newResponseMeta(res)}
+	if err := c.decode(res, s); err != nil {
+		return s, err
+	}
+	if !head.truncated {
+		if err := unsuccessfulError(head.b); err != nil {
+			return nil, err
+		}
+	}
+	e := &statusEntry{
+		etag:         res.Header.Get("ETag"),
+		lastModified: res.Header.Get("Last-Modified"),
+		status:       s,
+	}
+	h.Sum(e.sum[:0])
+	if prev != nil && prev.sum == e.sum {
+		return prev.response(true, s.Meta), nil
+	}
+	c.statusMu.Lock()
+	c.lastStatus = e
+	c.statusMu.Unlock()
+	return e.response(false, nil), nil
+}
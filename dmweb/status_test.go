@@ -0,0 +1,88 @@
+package dmweb
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStatusResponseLookup(t *testing.T) {
+	s := &GetStatusResponse{
+		HistoryCount: 20732,
+		EwonsCount:   2,
+		Ewons: []EwonStatus{
+			{ID: 2, Name: "Paris", HistoryCount: 2702},
+			{ID: 190, Name: "Brussels", HistoryCount: 18030},
+		},
+	}
+
+	e, ok := s.ByID(190)
+	if assert.True(t, ok) {
+		assert.Equal(t, "Brussels", e.Name)
+	}
+	_, ok = s.ByID(3)
+	assert.False(t, ok)
+
+	e, ok = s.ByName("Paris")
+	if assert.True(t, ok) {
+		assert.Equal(t, 2702, e.HistoryCount)
+	}
+	_, ok = s.ByName("paris")
+	assert.False(t, ok)
+
+	assert.Equal(t, 20732, s.TotalHistoryCount())
+}
+
+func TestGetStatusUnmodified(t *testing.T) {
+	body := `{"historyCount":1,"ewonsCount":1,"ewons":[{"id":1,"historyCount":1}]}`
+	var headers []http.Header
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		headers = append(headers, req.Header.Clone())
+		switch len(headers) {
+		case 1:
+			res := jsonResponse(200, body)
+			res.Header.Set("ETag", `"v1"`)
+			return res
+		case 2:
+			return jsonResponse(304, "")
+		case 3:
+			return jsonResponse(200, body)
+		default:
+			return jsonResponse(200, `{"historyCount":2,"ewonsCount":1,"ewons":[{"id":1,"historyCount":2}]}`)
+		}
+	})
+	m := &testMetrics{requests: make(map[string]int)}
+	assert.NoError(t, WithMetrics(m)(c))
+
+	s, err := c.GetStatus()
+	assert.NoError(t, err)
+	assert.False(t, s.Unmodified)
+	s.Ewons[0].HistoryCount = 100
+
+	for i := 0; i < 2; i++ {
+		s, err = c.GetStatus()
+		assert.NoError(t, err)
+		assert.True(t, s.Unmodified)
+		assert.Equal(t, 1, s.Ewons[0].HistoryCount)
+	}
+
+	s, err = c.GetStatus()
+	assert.NoError(t, err)
+	assert.False(t, s.Unmodified)
+	assert.Equal(t, 2, s.HistoryCount)
+
+	assert.Empty(t, headers[0].Get("If-None-Match"))
+	assert.Equal(t, `"v1"`, headers[1].Get("If-None-Match"))
+
+	// The 304 is a successful request.
+	assert.Equal(t, map[string]int{"getstatus 200": 3, "getstatus 304": 1}, m.requests)
+	assert.Equal(t, 0, m.errors)
+
+	// A 304 to an unconditional request is still an error.
+	c = newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(304, "")
+	})
+	_, err = c.GetEwons()
+	assert.Error(t, err)
+}
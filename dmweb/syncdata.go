@@ -0,0 +1,240 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// DefaultMaxSyncPages is the default maximum number of syncdata requests
+// made by a single SyncAllData call, see WithMaxSyncPages.
+const DefaultMaxSyncPages = 1000
+
+// ErrMaxSyncPagesExceeded is returned when the DataMailbox still reports
+// more data available after the maximum number of syncdata requests.
+var ErrMaxSyncPagesExceeded = errors.New("more data available after the maximum number of sync pages")
+
+// ErrTransactionExpired matches, with errors.Is, the error SyncData
+// returns when the DataMailbox rejects the last transaction ID, e.g.
+// because it expired during a long outage. Sync again without a
+// transaction ID, or see WithAutoResync. The DataMailbox rejects other
+// invalid parameters with the same error code, e.g. those added with
+// WithExtraParams, so syncing again without a transaction ID then fails
+// with that error.
+var ErrTransactionExpired = errors.New("transaction expired")
+
+// codeInvalidTransaction is the error code the DataMailbox responds with
+// to a syncdata request for an unknown or expired transaction, as for
+// other invalid parameters.
+const codeInvalidTransaction = 400
+
+// isTransactionExpired reports whether err, returned for a syncdata
+// request with a transaction ID, rejects that transaction, going by the
+// error code and HTTP status only: the DataMailbox reports the error
+// code with either an HTTP 200 or a 400.
+func isTransactionExpired(err error) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.Code == codeInvalidTransaction &&
+		(e.StatusCode == http.StatusOK || e.StatusCode == http.StatusBadRequest)
+}
+
+// transactionExpiredError is the error for a rejected transaction,
+// wrapping the APIError.
+type transactionExpiredError struct {
+	id  string
+	err error
+}
+
+func (e *transactionExpiredError) Error() string {
+	return fmt.Sprintf("transaction %s expired: %v", e.id, e.err)
+}
+
+func (e *transactionExpiredError) Unwrap() error {
+	return e.err
+}
+
+func (e *transactionExpiredError) Is(target error) bool {
+	return target == ErrTransactionExpired
+}
+
+// SyncNewTransaction syncs the data more recent than the transaction
+// lastTransactionID, or the oldest data in the DataMailbox when it's
+// empty, and has the DataMailbox create a new transaction for the
+// returned data. Its TransactionID is then the lastTransactionID of the
+// next call, so that every call moves the data window forward and no
+// data is returned twice. This is what incremental syncing should use,
+// and equals SyncData(lastTransactionID, true).
+func (c *Client) SyncNewTransaction(lastTransactionID string) (*SyncResponse, error) {
+	return c.SyncNewTransactionContext(context.Background(), lastTransactionID)
+}
+
+// SyncNewTransactionContext is like SyncNewTransaction, with ctx
+// controlling the request.
+func (c *Client) SyncNewTransactionContext(ctx context.Context, lastTransactionID string) (*SyncResponse, error) {
+	return c.SyncDataContext(ctx, lastTransactionID, true)
+}
+
+// SyncContinue syncs the data more recent than the transaction
+// lastTransactionID without creating a new transaction. The data window
+// doesn't move: calling it again with the same lastTransactionID returns
+// the same data, plus any data that arrived in the meantime. Use it to
+// peek at or retry a window without committing to it; passing its
+// TransactionID to a next call would skip data. It equals
+// SyncData(lastTransactionID, false).
+func (c *Client) SyncContinue(lastTransactionID string) (*SyncResponse, error) {
+	return c.SyncContinueContext(context.Background(), lastTransactionID)
+}
+
+// SyncContinueContext is like SyncContinue, with ctx controlling the
+// request.
+func (c *Client) SyncContinueContext(ctx context.Context, lastTransactionID string) (*SyncResponse, error) {
+	return c.SyncDataContext(ctx, lastTransactionID, false)
+}
+
+// SyncAllData calls SyncData until the DataMailbox reports no more data
+// available, each time continuing from the transaction of the previous
+// page, and returns all pages. An empty lastTransactionID starts with a
+// new transaction, like FirstSyncData.
+// The number of requests is capped, see WithMaxSyncPages. On error, the
+// pages received so far are returned along with it.
+func (c *Client) SyncAllData(lastTransactionID string) ([]*SyncResponse, error) {
+	return c.SyncAllDataContext(context.Background(), lastTransactionID)
+}
+
+// SyncAllDataContext is like SyncAllData, with ctx controlling the
+// requests.
+func (c *Client) SyncAllDataContext(ctx context.Context, lastTransactionID string) ([]*SyncResponse, error) {
+	var pages []*SyncResponse
+	err := c.SyncAllDataFunc(ctx, lastTransactionID, func(s *SyncResponse) error {
+		pages = append(pages, s)
+		return nil
+	})
+	return pages, err
+}
+
+// SyncAllDataFunc is like SyncAllDataContext, but passes every page to
+// fn as it arrives instead of buffering them. It stops at and returns
+// the first error fn returns.
+func (c *Client) SyncAllDataFunc(ctx context.Context, lastTransactionID string, fn func(*SyncResponse) error) error {
+	return c.syncAllDataFunc(ctx, lastTransactionID, c.syncPagesLimit(), fn)
+}
+
+// syncPagesLimit returns the maximum number of syncdata requests of a
+// SyncAllData call, see WithMaxSyncPages.
+func (c *Client) syncPagesLimit() int {
+	if c.maxSyncPages <= 0 {
+		return DefaultMaxSyncPages
+	}
+	return c.maxSyncPages
+}
+
+// syncAllDataFunc is SyncAllDataFunc, making at most max requests.
+func (c *Client) syncAllDataFunc(ctx context.Context, lastTransactionID string, max int, fn func(*SyncResponse) error) error {
+	for i := 0; i < max; i++ {
+		s, err := c.SyncDataContext(ctx, lastTransactionID, true)
+		if err != nil {
+			return err
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+		if !s.MoreDataAvailable {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		lastTransactionID = s.TransactionID
+	}
+	return ErrMaxSyncPagesExceeded
+}
+
+// SyncPages syncs all data of the account, starting with a new
+// transaction, and streams every page on the returned channel while the
+// DataMailbox reports more data available, like SyncAllData. The next
+// page is requested once the previous one is received.
+// The page channel is closed when syncing is done. A terminal error,
+// including the cancellation of ctx, is then available on the error
+// channel, which is closed without a value on success:
+//
+//	pages, errc := c.SyncPages(ctx)
+//	for s := range pages {
+//		...
+//	}
+//	if err := <-errc; err != nil {
+//		...
+//	}
+func (c *Client) SyncPages(ctx context.Context) (<-chan *SyncResponse, <-chan error) {
+	pages := make(chan *SyncResponse)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(pages)
+		err := c.SyncAllDataFunc(ctx, "", func(s *SyncResponse) error {
+			select {
+			case pages <- s:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errc <- err
+		}
+	}()
+	return pages, errc
+}
+
+// SyncPage is a page streamed by SyncDataStream. Exactly one of Response
+// and Err is set.
+type SyncPage struct {
+	Response *SyncResponse
+	Err      error
+}
+
+// SyncDataStream is like SyncAllDataContext, but streams the pages on
+// the returned channel instead of buffering them. The first page is
+// requested before returning, so that errors like invalid credentials
+// are returned directly. Every next page is requested as soon as the
+// previous one is received from the channel.
+// The first page counts against WithMaxSyncPages like the others.
+// The channel is closed once the DataMailbox reports no more data
+// available, or after a page carrying an error, including the
+// cancellation of ctx. That page is always sent, so the channel must be
+// received from until it is closed.
+func (c *Client) SyncDataStream(ctx context.Context, lastTransactionID string) (<-chan SyncPage, error) {
+	s, err := c.SyncDataContext(ctx, lastTransactionID, true)
+	if err != nil {
+		return nil, err
+	}
+	pages := make(chan SyncPage)
+	go func() {
+		defer close(pages)
+		send := func(p SyncPage) bool {
+			select {
+			case pages <- p:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		if !send(SyncPage{Response: s}) {
+			pages <- SyncPage{Err: ctx.Err()}
+			return
+		}
+		if !s.MoreDataAvailable {
+			return
+		}
+		err := c.syncAllDataFunc(ctx, s.TransactionID, c.syncPagesLimit()-1, func(s *SyncResponse) error {
+			if !send(SyncPage{Response: s}) {
+				return ctx.Err()
+			}
+			return nil
+		})
+		if err != nil {
+			pages <- SyncPage{Err: err}
+		}
+	}()
+	return pages, nil
+}
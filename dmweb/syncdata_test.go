@@ -0,0 +1,233 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncPages(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		switch req.URL.Query().Get("lastTransactionId") {
+		case "":
+			return jsonResponse(200, `{"success":true,"transactionId":"1","moreDataAvailable":true,"ewons":[]}`)
+		case "1":
+			return jsonResponse(200, `{"success":true,"transactionId":"2","moreDataAvailable":false,"ewons":[]}`)
+		}
+		t.Errorf("unexpected request %s", req.URL)
+		return jsonResponse(500, `{"success":false,"code":500,"message":"unexpected"}`)
+	})
+
+	pages, errc := c.SyncPages(context.Background())
+	var ids []string
+	for s := range pages {
+		ids = append(ids, s.TransactionID)
+	}
+	assert.NoError(t, <-errc)
+	assert.Equal(t, []string{"1", "2"}, ids)
+}
+
+func TestSyncPagesCancel(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(200, `{"success":true,"transactionId":"1","moreDataAvailable":true,"ewons":[]}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pages, errc := c.SyncPages(ctx)
+	<-pages
+	cancel()
+	for range pages {
+	}
+	assert.True(t, errors.Is(<-errc, context.Canceled))
+}
+
+func TestSyncAllData(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "true", req.URL.Query().Get("createTransaction"))
+		switch req.URL.Query().Get("lastTransactionId") {
+		case "1":
+			return jsonResponse(200, `{"success":true,"transactionId":"2","moreDataAvailable":true,"ewons":[]}`)
+		case "2":
+			return jsonResponse(200, `{"success":true,"transactionId":"3","moreDataAvailable":false,"ewons":[]}`)
+		}
+		t.Errorf("unexpected request %s", req.URL)
+		return jsonResponse(500, `{"success":false,"code":500,"message":"unexpected"}`)
+	})
+
+	pages, err := c.SyncAllData("1")
+	assert.NoError(t, err)
+	if assert.Len(t, pages, 2) {
+		assert.Equal(t, "2", pages[0].TransactionID)
+		assert.Equal(t, "3", pages[1].TransactionID)
+	}
+}
+
+func TestSyncAllDataMaxPages(t *testing.T) {
+	n := 0
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		n++
+		return jsonResponse(200, fmt.Sprintf(`{"success":true,"transactionId":"%d","moreDataAvailable":true,"ewons":[]}`, n))
+	})
+	assert.NoError(t, WithMaxSyncPages(3)(c))
+
+	pages, err := c.SyncAllData("")
+	assert.Equal(t, ErrMaxSyncPagesExceeded, err)
+	assert.Len(t, pages, 3)
+	assert.Equal(t, 3, n)
+}
+
+func TestSyncAllDataFunc(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(200, `{"success":true,"transactionId":"1","moreDataAvailable":true,"ewons":[]}`)
+	})
+
+	stop := errors.New("stop")
+	n := 0
+	err := c.SyncAllDataFunc(context.Background(), "", func(s *SyncResponse) error {
+		n++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestSyncDataStream(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		switch req.URL.Query().Get("lastTransactionId") {
+		case "1":
+			return jsonResponse(200, `{"success":true,"transactionId":"2","moreDataAvailable":true,"ewons":[]}`)
+		case "2":
+			return jsonResponse(200, `{"success":true,"transactionId":"3","moreDataAvailable":true,"ewons":[]}`)
+		case "3":
+			return jsonResponse(200, `{"success":true,"transactionId":"4","moreDataAvailable":false,"ewons":[]}`)
+		}
+		return jsonResponse(401, `{"success":false,"code":401,"message":"Invalid credentials"}`)
+	})
+
+	pages, err := c.SyncDataStream(context.Background(), "1")
+	assert.NoError(t, err)
+	var ids []string
+	for p := range pages {
+		assert.NoError(t, p.Err)
+		ids = append(ids, p.Response.TransactionID)
+	}
+	assert.Equal(t, []string{"2", "3", "4"}, ids)
+
+	_, err = c.SyncDataStream(context.Background(), "")
+	assert.EqualError(t, err, "Invalid credentials")
+}
+
+func TestSyncDataStreamCancel(t *testing.T) {
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(200, `{"success":true,"transactionId":"1","moreDataAvailable":true,"ewons":[]}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pages, err := c.SyncDataStream(ctx, "")
+	assert.NoError(t, err)
+	<-pages
+	cancel()
+	var last SyncPage
+	for p := range pages {
+		last = p
+	}
+	assert.True(t, errors.Is(last.Err, context.Canceled), "%v", last.Err)
+}
+
+func TestSyncDataStreamMaxPages(t *testing.T) {
+	n := 0
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		n++
+		return jsonResponse(200, fmt.Sprintf(`{"success":true,"transactionId":"%d","moreDataAvailable":true,"ewons":[]}`, n))
+	})
+	assert.NoError(t, WithMaxSyncPages(2)(c))
+
+	pages, err := c.SyncDataStream(context.Background(), "")
+	assert.NoError(t, err)
+	var got []SyncPage
+	for p := range pages {
+		got = append(got, p)
+	}
+	assert.Equal(t, 2, n)
+	if assert.Len(t, got, 3) {
+		assert.Equal(t, "2", got[1].Response.TransactionID)
+		assert.Equal(t, ErrMaxSyncPagesExceeded, got[2].Err)
+	}
+}
+
+func TestSyncDataTransactionExpired(t *testing.T) {
+	var last []string
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		id := req.URL.Query().Get("lastTransactionId")
+		last = append(last, id)
+		if id == "123" {
+			return jsonResponse(400, `{"success":false,"code":400,"message":"Invalid transaction"}`)
+		}
+		return jsonResponse(200, `{"success":true,"transactionId":"456","moreDataAvailable":false,"ewons":[]}`)
+	})
+
+	_, err := c.SyncData("123", true)
+	assert.True(t, errors.Is(err, ErrTransactionExpired))
+	var e *APIError
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, 400, e.Code)
+	}
+	assert.EqualError(t, err, "transaction 123 expired: Invalid transaction")
+
+	var warnings []error
+	assert.NoError(t, WithAutoResync()(c))
+	assert.NoError(t, WithWarningHandler(func(err error) { warnings = append(warnings, err) })(c))
+	last = nil
+	s, err := c.SyncData("123", true)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "456", s.TransactionID)
+	}
+	assert.Equal(t, []string{"123", ""}, last)
+	assert.Len(t, warnings, 1)
+}
+
+func TestSyncDataBadRequestNotExpired(t *testing.T) {
+	var last []string
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		last = append(last, req.URL.Query().Get("lastTransactionId"))
+		return jsonResponse(400, `{"success":false,"code":400,"message":"Invalid parameter newFlag"}`)
+	})
+	_, err := c.SyncData("123", true)
+	assert.True(t, errors.Is(err, ErrTransactionExpired))
+
+	// The new transaction fails with the same error, which is returned.
+	assert.NoError(t, WithAutoResync()(c))
+	last = nil
+	_, err = c.SyncData("123", true)
+	assert.False(t, errors.Is(err, ErrTransactionExpired))
+	assert.EqualError(t, err, "Invalid parameter newFlag")
+	assert.Equal(t, []string{"123", ""}, last)
+
+	// Other HTTP statuses aren't taken for an expired transaction.
+	c = newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(502, `{"success":false,"code":400,"message":"Bad gateway"}`)
+	})
+	_, err = c.SyncData("123", true)
+	assert.False(t, errors.Is(err, ErrTransactionExpired))
+}
+
+func TestSyncNewTransactionAndContinue(t *testing.T) {
+	var queries []string
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		q := req.URL.Query()
+		queries = append(queries, q.Get("lastTransactionId")+"/"+q.Get("createTransaction"))
+		return jsonResponse(200, `{"success":true,"transactionId":"2","ewons":[]}`)
+	})
+	s, err := c.SyncNewTransaction("1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", s.TransactionID)
+	_, err = c.SyncContinue("1")
+	assert.NoError(t, err)
+	_, err = c.SyncNewTransaction("")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1/true", "1/", "/true"}, queries)
+}
@@ -0,0 +1,116 @@
+package dmweb
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CursorStore persists the ID of the last synced transaction, see Syncer.
+type CursorStore interface {
+	// Load returns the saved transaction ID, or an empty string when
+	// none was saved yet.
+	Load() (string, error)
+	// Save saves the transaction ID.
+	Save(transactionID string) error
+}
+
+// Syncer syncs the data of the account page by page, persisting the
+// transaction ID of every page once it has been processed, so that
+// syncing resumes where it left off across restarts.
+type Syncer struct {
+	client *Client
+	store  CursorStore
+}
+
+// NewSyncer returns a Syncer syncing with c and persisting the
+// transaction ID to store.
+func NewSyncer(c *Client, store CursorStore) *Syncer {
+	return &Syncer{client: c, store: store}
+}
+
+// Next syncs the page following the saved transaction, or starts a new
+// transaction when none was saved, and passes it to fn. The transaction
+// ID of the page is saved only when fn succeeds, so that a page whose
+// processing failed, or was interrupted by a crash, is synced again by
+// the next call. The page is returned; its MoreDataAvailable field
+// tells whether to call Next again right away.
+func (s *Syncer) Next(ctx context.Context, fn func(*SyncResponse) error) (*SyncResponse, error) {
+	id, err := s.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.client.SyncDataContext(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(r); err != nil {
+		return r, err
+	}
+	if err := s.store.Save(r.TransactionID); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// MemoryCursorStore is a CursorStore keeping the transaction ID in
+// memory. It is safe for concurrent use; the zero value is ready to use.
+type MemoryCursorStore struct {
+	mu sync.Mutex
+	id string
+}
+
+// Load implements CursorStore.
+func (m *MemoryCursorStore) Load() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.id, nil
+}
+
+// Save implements CursorStore.
+func (m *MemoryCursorStore) Save(transactionID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.id = transactionID
+	return nil
+}
+
+// FileCursorStore is a CursorStore keeping the transaction ID in the
+// file at Path. The file is replaced atomically on Save, so a crash
+// never leaves it half written.
+type FileCursorStore struct {
+	Path string
+}
+
+// Load implements CursorStore. A missing file means no transaction ID
+// was saved yet.
+func (f FileCursorStore) Load() (string, error) {
+	b, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// Save implements CursorStore.
+func (f FileCursorStore) Save(transactionID string) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(f.Path), filepath.Base(f.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(transactionID + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
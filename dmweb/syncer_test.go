@@ -0,0 +1,66 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncer(t *testing.T) {
+	var got []string
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		last := req.URL.Query().Get("lastTransactionId")
+		got = append(got, last)
+		n, _ := strconv.Atoi(last)
+		return jsonResponse(200, `{"success":true,"transactionId":"`+strconv.Itoa(n+1)+`","moreDataAvailable":true,"ewons":[]}`)
+	})
+	store := &MemoryCursorStore{}
+	s := NewSyncer(c, store)
+	ok := func(*SyncResponse) error { return nil }
+
+	r, err := s.Next(context.Background(), ok)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "1", r.TransactionID)
+	}
+
+	_, err = s.Next(context.Background(), func(*SyncResponse) error { return errors.New("processing failed") })
+	assert.EqualError(t, err, "processing failed")
+	id, _ := store.Load()
+	assert.Equal(t, "1", id)
+
+	_, err = s.Next(context.Background(), ok)
+	assert.NoError(t, err)
+	id, _ = store.Load()
+	assert.Equal(t, "2", id)
+	assert.Equal(t, []string{"", "1", "1"}, got)
+}
+
+func TestFileCursorStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dmweb")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	f := FileCursorStore{Path: filepath.Join(dir, "cursor")}
+	id, err := f.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "", id)
+
+	assert.NoError(t, f.Save("123456"))
+	assert.NoError(t, f.Save("123457"))
+	id, err = f.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "123457", id)
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
@@ -0,0 +1,27 @@
+package dmweb
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// timeout returns the timeout for requests to endpoint, or 0 for none.
+func (c *Client) timeout(endpoint string) time.Duration {
+	if d, ok := c.timeouts[endpoint]; ok {
+		return d
+	}
+	return c.defaultTimeout
+}
+
+// cancelBody releases the context of a request once its body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,70 @@
+package dmweb
+
+import (
+	"fmt"
+	"time"
+)
+
+// warn reports a warning to the configured handler, if any, and logs
+// it.
+func (c *Client) warn(err error) {
+	c.log().Warnf("dmweb: %v", err)
+	if c.onWarning != nil {
+		c.onWarning(err)
+	}
+}
+
+// zones loads and caches time zones by name.
+type zones map[string]*time.Location
+
+func (z zones) load(name string) (*time.Location, error) {
+	if loc, ok := z[name]; ok {
+		return loc, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	z[name] = loc
+	return loc, nil
+}
+
+// location returns the time zone of an eWON, or nil when the eWON
+// doesn't report one or it can't be loaded.
+func (c *Client) location(z zones, ewonID int, timeZone string) *time.Location {
+	if timeZone == "" {
+		return nil
+	}
+	loc, err := z.load(timeZone)
+	if err != nil {
+		c.warn(fmt.Errorf("ewon %d: leaving timestamps untouched: %w", ewonID, err))
+		return nil
+	}
+	return loc
+}
+
+// localize reinterprets the wall clock of UTC history timestamps as
+// local time in loc.
+func localize(history []HistoryPoint, loc *time.Location) {
+	for i, h := range history {
+		if h.Date.Location() != time.UTC {
+			continue
+		}
+		y, m, d := h.Date.Date()
+		history[i].serverDate = h.Date
+		history[i].Date = time.Date(y, m, d, h.Date.Hour(), h.Date.Minute(), h.Date.Second(), h.Date.Nanosecond(), loc)
+	}
+}
+
+// localizeEwons applies localize to the history of every tag of the
+// eWONs that report a time zone.
+func (c *Client) localizeEwons(ewons []DataEwon) {
+	z := zones{}
+	for _, e := range ewons {
+		if loc := c.location(z, e.ID, e.TimeZone); loc != nil {
+			for _, t := range e.Tags {
+				localize(t.History, loc)
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package dmweb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithEwonLocalTime(t *testing.T) {
+	body := `{"success":true,"transactionId":"1","moreDataAvailable":false,"ewons":[{
+		"id":1,"name":"Ewon1","timeZone":"Europe/Brussels",
+		"tags":[{"id":10,"name":"TAG","history":[{"date":"2018-11-08T14:17:58Z","value":1}]}]
+	},{
+		"id":2,"name":"Ewon2","timeZone":"Mars/Olympus_Mons",
+		"tags":[{"id":20,"name":"TAG","history":[{"date":"2018-11-08T14:17:58Z","value":1}]}]
+	},{
+		"id":3,"name":"Ewon3",
+		"tags":[{"id":30,"name":"TAG","history":[{"date":"2018-11-08T14:17:58Z","value":1}]}]
+	}]}`
+	utc := time.Date(2018, 11, 8, 14, 17, 58, 0, time.UTC)
+
+	// Timestamps are untouched by default.
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		return jsonResponse(200, body)
+	})
+	s, err := c.FirstSyncData()
+	assert.NoError(t, err)
+	assert.True(t, utc.Equal(s.Ewons[0].Tags[0].History[0].Date))
+
+	var warnings []error
+	assert.NoError(t, WithEwonLocalTime()(c))
+	assert.NoError(t, WithWarningHandler(func(err error) { warnings = append(warnings, err) })(c))
+	s, err = c.FirstSyncData()
+	assert.NoError(t, err)
+
+	// 14:17:58 in Brussels in November is 13:17:58 UTC.
+	d := s.Ewons[0].Tags[0].History[0].Date
+	assert.Equal(t, "Europe/Brussels", d.Location().String())
+	assert.True(t, utc.Add(-time.Hour).Equal(d))
+
+	assert.True(t, utc.Equal(s.Ewons[1].Tags[0].History[0].Date))
+	assert.True(t, utc.Equal(s.Ewons[2].Tags[0].History[0].Date))
+	if assert.Len(t, warnings, 1) {
+		assert.Contains(t, warnings[0].Error(), "ewon 2")
+	}
+
+	c.Client = NewTestClient(func(req *http.Request) *http.Response {
+		return jsonResponse(200, body)
+	})
+	g, err := c.GetData(nil)
+	assert.NoError(t, err)
+	assert.True(t, utc.Add(-time.Hour).Equal(g.Ewons[0].Tags[0].History[0].Date))
+}
+
+func TestWithEwonLocalTimePaging(t *testing.T) {
+	page := func(more bool, points ...string) *http.Response {
+		h := ""
+		for i, p := range points {
+			if i > 0 {
+				h += ","
+			}
+			h += `{"date":"2018-11-08T` + p + `:00Z","value":1` + p[3:] + `}`
+		}
+		m := "false"
+		if more {
+			m = "true"
+		}
+		return jsonResponse(200, `{"success":true,"moreDataAvailable":`+m+`,"ewons":[{
+			"id":1,"name":"Ewon1","timeZone":"Europe/Brussels",
+			"tags":[{"id":10,"name":"TAG","history":[`+h+`]}]}]}`)
+	}
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		q := req.URL.Query()
+		switch q.Get("from") + " " + q.Get("to") {
+		// Paging falls back to the newest timestamp, as returned.
+		case "2018-11-08T14:00:00Z ":
+			return page(true, "14:00", "14:10")
+		case "2018-11-08T14:10:00Z ":
+			return page(false, "14:10", "14:20")
+		// Windows.
+		case "2018-11-08T14:00:00Z 2018-11-08T14:10:00Z":
+			return page(false, "14:00", "14:10")
+		case "2018-11-08T14:10:00Z 2018-11-08T14:20:00Z":
+			return page(false, "14:10", "14:20")
+		}
+		t.Errorf("unexpected request %s", req.URL)
+		return jsonResponse(400, `{"success":false,"code":400,"message":"unexpected"}`)
+	})
+	assert.NoError(t, WithEwonLocalTime()(c))
+	values := func(rs []Record) []float64 {
+		var vs []float64
+		for _, r := range rs {
+			vs = append(vs, r.Value)
+		}
+		return vs
+	}
+
+	from := time.Date(2018, 11, 8, 14, 0, 0, 0, time.UTC)
+	d, err := c.GetDataAll(context.Background(), GetDataParams{From: &from})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{100, 110, 120}, values(d.Records()))
+	// 14:00 in Brussels in November is 13:00 UTC.
+	assert.True(t, from.Add(-time.Hour).Equal(d.Records()[0].Date))
+
+	var exported []Record
+	err = c.ExportAll(context.Background(), from, time.Time{}, 0, func(rs []Record) error {
+		exported = append(exported, rs...)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{100, 110, 120}, values(exported))
+
+	d, err = c.GetDataByWindows(context.Background(), 0, from, from.Add(20*time.Minute), 10*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{100, 110, 120}, values(d.Records()))
+}
@@ -0,0 +1,51 @@
+package dmweb
+
+import (
+	"errors"
+	"net/http"
+)
+
+// errorUnsupportedTransport is returned when WithProxy or WithTLSConfig
+// is used with an HTTP client whose transport isn't an *http.Transport.
+var errorUnsupportedTransport = errors.New("proxy and TLS options require the HTTP client's transport to be an *http.Transport")
+
+// configureTransport applies the proxy and TLS configuration of the
+// options to the transport of the HTTP client, and, when the client
+// created it, the connection tuning. The HTTP client and its transport
+// are copied rather than modified, as they may be shared.
+func (c *Client) configureTransport(created bool) error {
+	tune := created && (c.maxIdleConns > 0 || c.idleConnTimeout > 0 || c.disableKeepAlives)
+	if c.proxyURL == nil && c.tlsConfig == nil && !tune {
+		return nil
+	}
+	rt := c.Client.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return errorUnsupportedTransport
+	}
+	t = t.Clone()
+	if c.proxyURL != nil {
+		t.Proxy = http.ProxyURL(c.proxyURL)
+	}
+	if c.tlsConfig != nil {
+		t.TLSClientConfig = c.tlsConfig.Clone()
+	}
+	if tune {
+		if c.maxIdleConns > 0 {
+			t.MaxIdleConns = c.maxIdleConns
+			t.MaxIdleConnsPerHost = c.maxIdleConns
+		}
+		if c.idleConnTimeout > 0 {
+			t.IdleConnTimeout = c.idleConnTimeout
+		}
+		t.DisableKeepAlives = c.disableKeepAlives
+	}
+	h := *c.Client
+	h.Transport = t
+	c.Client = &h
+	c.ownsTransport = true
+	return nil
+}
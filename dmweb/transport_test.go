@@ -0,0 +1,69 @@
+package dmweb
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithProxyAndTLSConfig(t *testing.T) {
+	cfg := &tls.Config{ServerName: "data.talk2m.com"}
+	c, err := New(nil, "aid", "username", "password", "devid",
+		WithProxy("http://proxy.example.com:3128"), WithTLSConfig(cfg))
+	assert.NoError(t, err)
+	tr, ok := c.Client.Transport.(*http.Transport)
+	if assert.True(t, ok) {
+		p, err := tr.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "data.talk2m.com"}})
+		assert.NoError(t, err)
+		assert.Equal(t, "http://proxy.example.com:3128", p.String())
+		assert.Equal(t, "data.talk2m.com", tr.TLSClientConfig.ServerName)
+		assert.NotSame(t, cfg, tr.TLSClientConfig)
+	}
+	assert.Equal(t, DefaultHTTPTimeout, c.Client.Timeout)
+	assert.NotSame(t, http.DefaultTransport, c.Client.Transport)
+
+	base := &http.Transport{MaxIdleConns: 7}
+	h := &http.Client{Transport: base}
+	c, err = New(nil, "aid", "username", "password", "devid",
+		WithTLSConfig(cfg), WithHTTPClient(h))
+	assert.NoError(t, err)
+	assert.NotSame(t, h, c.Client)
+	assert.Same(t, base, h.Transport)
+	assert.True(t, base.TLSClientConfig == nil || base.TLSClientConfig.ServerName == "")
+	tr = c.Client.Transport.(*http.Transport)
+	assert.Equal(t, 7, tr.MaxIdleConns)
+	assert.Equal(t, "data.talk2m.com", tr.TLSClientConfig.ServerName)
+
+	_, err = New(NewTestClient(nil), "aid", "username", "password", "devid", WithTLSConfig(cfg))
+	assert.Equal(t, errorUnsupportedTransport, err)
+	_, err = New(nil, "aid", "username", "password", "devid", WithProxy("proxy"))
+	assert.Error(t, err)
+	_, err = New(nil, "aid", "username", "password", "devid", WithTLSConfig(nil))
+	assert.Error(t, err)
+}
+
+func TestConnectionTuning(t *testing.T) {
+	c, err := New(nil, "aid", "username", "password", "devid",
+		WithMaxIdleConns(2), WithIdleConnTimeout(time.Second), WithDisableKeepAlives())
+	assert.NoError(t, err)
+	tr := c.Client.Transport.(*http.Transport)
+	assert.Equal(t, 2, tr.MaxIdleConns)
+	assert.Equal(t, 2, tr.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Second, tr.IdleConnTimeout)
+	assert.True(t, tr.DisableKeepAlives)
+	assert.False(t, http.DefaultTransport.(*http.Transport).DisableKeepAlives)
+
+	h := &http.Client{}
+	c, err = New(h, "aid", "username", "password", "devid", WithDisableKeepAlives())
+	assert.NoError(t, err)
+	assert.Same(t, h, c.Client)
+
+	_, err = New(nil, "aid", "username", "password", "devid", WithMaxIdleConns(0))
+	assert.Error(t, err)
+	_, err = New(nil, "aid", "username", "password", "devid", WithIdleConnTimeout(0))
+	assert.Error(t, err)
+}
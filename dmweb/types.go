@@ -1,8 +1,14 @@
 package dmweb
 
 import (
+	"crypto/tls"
+	"encoding/json"
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 // Client represents a DMWeb API client
@@ -11,21 +17,65 @@ type Client struct {
 	AccountID string
 	Username  string
 	Password  string
+	Token     string
 	DevID     string
 	baseURL   string
 	userAgent string
+	auth      authStrategy
+	endpoints map[string]string
+	methods   map[string]string
+
+	readBufferSize int
+	httpTimeout    time.Duration
+
+	loggingIntervals  map[string]time.Duration
+	maxSyncPages      int
+	retry             retryPolicy
+	ewonLocalTime     bool
+	onWarning         func(error)
+	limiter           *rate.Limiter
+	requestHooks      []func(*http.Request)
+	responseHooks     []func(*http.Response, time.Duration, error)
+	strictValidation  bool
+	defaultTimeout    time.Duration
+	timeouts          map[string]time.Duration
+	logger            Logger
+	ewonNameFold      bool
+	metrics           Metrics
+	noCompression     bool
+	autoResync        bool
+	cache             *responseCache
+	proxyURL          *url.URL
+	tlsConfig         *tls.Config
+	strictDecoding    bool
+	useNumber         bool
+	maxIdleConns      int
+	idleConnTimeout   time.Duration
+	disableKeepAlives bool
+	extraParams       url.Values
+	defaultParams     map[string]url.Values
+	maxResponseSize   int64
+	dryRun            bool
+	clock             func() time.Time
+	validateResponses bool
+	ewonFilter        *ewonFilter
+	ownsTransport     bool
+	statusMu          sync.Mutex
+	lastStatus        *statusEntry
+	closed            int32
 }
 
 // Tag represents an EWON tag
 type Tag struct {
-	ID          int     `json:"id"`
-	Name        string  `json:"name"`
-	DataType    string  `json:"dataType"`
-	Description string  `json:"description"`
-	AlarmHint   string  `json:"alarmHint"`
-	Value       float64 `json:"value"`
-	Quality     string  `json:"quality"`
-	EwonTagID   int     `json:"ewonTagId"`
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	DataType    DataType `json:"dataType"`
+	Description string   `json:"description"`
+	AlarmHint   string   `json:"alarmHint"`
+	Value       float64  `json:"value"`
+	Quality     Quality  `json:"quality"`
+	EwonTagID   int      `json:"ewonTagId"`
+	number      json.Number
 }
 
 // Tags ..
@@ -46,15 +96,24 @@ type Ewons []*Ewon
 
 // GetStatusResponse represents a status response
 type GetStatusResponse struct {
-	HistoryCount int `json:"historyCount"`
-	EwonsCount   int `json:"ewonsCount"`
-	Ewons        []struct {
-		ID               int       `json:"id"`
-		Name             string    `json:"name"`
-		HistoryCount     int       `json:"historyCount"`
-		FirstHistoryDate time.Time `json:"firstHistoryDate"`
-		LastHistoryDate  time.Time `json:"lastHistoryDate"`
-	} `json:"ewons"`
+	Success      bool         `json:"success"`
+	HistoryCount int          `json:"historyCount"`
+	EwonsCount   int          `json:"ewonsCount"`
+	Ewons        []EwonStatus `json:"ewons"`
+	// Unmodified is set when the status didn't change since the
+	// previous GetStatus of the client, see GetStatus.
+	Unmodified bool          `json:"-"`
+	Meta       *ResponseMeta `json:"-"`
+}
+
+// EwonStatus represents the storage consumption of an eWON in a status
+// response
+type EwonStatus struct {
+	ID               int       `json:"id"`
+	Name             string    `json:"name"`
+	HistoryCount     int       `json:"historyCount"`
+	FirstHistoryDate time.Time `json:"firstHistoryDate"`
+	LastHistoryDate  time.Time `json:"lastHistoryDate"`
 }
 
 // GetDataResponse represents a successful response
@@ -62,58 +121,121 @@ type GetStatusResponse struct {
 type GetDataResponse struct {
 	Success           bool `json:"success"`
 	MoreDataAvailable bool `json:"moreDataAvailable"`
-	Ewons             []struct {
-		ID   int    `json:"id"`
-		Name string `json:"name"`
-		Tags []struct {
-			ID          int    `json:"id"`
-			Name        string `json:"name"`
-			DataType    string `json:"dataType"`
-			Description string `json:"description"`
-			AlarmHint   string `json:"alarmHint"`
-			Value       int    `json:"value"`
-			Quality     string `json:"quality"`
-			EwonTagID   int    `json:"ewonTagId"`
-			History     []struct {
-				Date    time.Time `json:"date,omitempty"`
-				Value   int       `json:"value"`
-				Quality string    `json:"quality,omitempty"`
-			} `json:"history"`
-		} `json:"tags"`
-		LastSynchroDate time.Time `json:"lastSynchroDate"`
-		TimeZone        string    `json:"timeZone"`
-	} `json:"ewons"`
+	// MoreDataID identifies the rest of the data when MoreDataAvailable
+	// is set, see GetDataParams.MoreData. It is empty when the
+	// DataMailbox doesn't return one.
+	MoreDataID MoreDataID `json:"moreDataId,omitempty"`
+	// MoreDataIDs holds, for a request with several TagIDs, the
+	// MoreDataID of every tag with more data available, by tag ID. The
+	// rest of the data of a tag is requested with its TagID and
+	// MoreData. MoreDataID is empty then.
+	MoreDataIDs map[int]MoreDataID `json:"-"`
+	Ewons       []DataEwon         `json:"ewons"`
+	Meta        *ResponseMeta      `json:"-"`
 }
 
 // SyncResponse represents a successful response
 // to the syncdata endpoint.
 type SyncResponse struct {
-	Success           bool   `json:"success"`
-	TransactionID     string `json:"transactionId"`
-	MoreDataAvailable bool   `json:"moreDataAvailable"`
-	Ewons             []struct {
-		ID   int    `json:"id"`
-		Name string `json:"name"`
-		Tags []struct {
-			ID          int     `json:"id"`
-			Name        string  `json:"name"`
-			DataType    string  `json:"dataType"`
-			Description string  `json:"description"`
-			AlarmHint   string  `json:"alarmHint"`
-			Value       float64 `json:"value"`
-			Quality     string  `json:"quality"`
-			EwonTagID   int     `json:"ewonTagId"`
-			History     []struct {
-				Date     time.Time `json:"date"`
-				DataType string    `json:"dataType"`
-				Value    float64   `json:"value"`
-				Quality  string    `json:"quality"`
-			} `json:"history"`
-		} `json:"tags"`
-		LastSynchroDate time.Time `json:"lastSynchroDate"`
-	} `json:"ewons"`
+	Success           bool          `json:"success"`
+	TransactionID     string        `json:"transactionId"`
+	MoreDataAvailable bool          `json:"moreDataAvailable"`
+	Ewons             []DataEwon    `json:"ewons"`
+	Meta              *ResponseMeta `json:"-"`
+}
+
+// DataEwon represents an eWON, with its tags, in a response
+// to the getdata or syncdata endpoint
+type DataEwon struct {
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	Tags            []DataTag `json:"tags"`
+	LastSynchroDate time.Time `json:"lastSynchroDate"`
+	TimeZone        string    `json:"timeZone"`
+}
+
+// DataTag represents a tag, with its history, in a response
+// to the getdata or syncdata endpoint
+type DataTag struct {
+	ID          int            `json:"id"`
+	Name        string         `json:"name"`
+	DataType    DataType       `json:"dataType"`
+	Description string         `json:"description"`
+	AlarmHint   string         `json:"alarmHint"`
+	Value       float64        `json:"value"`
+	Quality     Quality        `json:"quality"`
+	EwonTagID   int            `json:"ewonTagId"`
+	History     []HistoryPoint `json:"history"`
+	number      json.Number
+}
+
+// TagHistory is the history of a single tag, identified by the IDs and
+// names of the tag and its eWON.
+type TagHistory struct {
+	EwonID   int            `json:"ewonId"`
+	EwonName string         `json:"ewonName"`
+	TagID    int            `json:"tagId"`
+	TagName  string         `json:"tagName"`
+	History  []HistoryPoint `json:"history"`
+}
+
+// HistoryPoint is a single logged value of a tag, as found in the
+// history of both getdata and syncdata responses.
+type HistoryPoint struct {
+	Date     time.Time `json:"date"`
+	DataType DataType  `json:"dataType,omitempty"`
+	Value    float64   `json:"value"`
+	Quality  Quality   `json:"quality,omitempty"`
+	number   json.Number
+	// serverDate is the date as returned by the DataMailbox, when Date
+	// was localized, see WithEwonLocalTime.
+	serverDate time.Time
+}
+
+// rawDate returns the date of the point as returned by the DataMailbox,
+// which pages on these.
+func (h HistoryPoint) rawDate() time.Time {
+	if !h.serverDate.IsZero() {
+		return h.serverDate
+	}
+	return h.Date
+}
+
+// CleanParams are the parameters of a clean request. Nil or empty fields
+// are left out of the request.
+type CleanParams struct {
+	// EwonID restricts the deletion to the data of a single eWON.
+	EwonID *int
+	// TransactionID restricts the deletion to the data up to and
+	// including this transaction.
+	TransactionID string
 }
 
+// CleanResponse represents a successful response
+// to the clean endpoint.
+type CleanResponse struct {
+	Success bool          `json:"success"`
+	Meta    *ResponseMeta `json:"-"`
+}
+
+// ResponseMeta holds the metadata of the HTTP response a response was
+// decoded from, e.g. to read rate limit headers. It is not part of the
+// JSON encoding of responses.
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+}
+
+// newResponseMeta returns the metadata of res.
+func newResponseMeta(res *http.Response) *ResponseMeta {
+	return &ResponseMeta{StatusCode: res.StatusCode, Header: res.Header}
+}
+
+func (s *GetStatusResponse) setMeta(m *ResponseMeta) { s.Meta = m }
+func (d *GetDataResponse) setMeta(m *ResponseMeta)   { d.Meta = m }
+func (s *SyncResponse) setMeta(m *ResponseMeta)      { s.Meta = m }
+func (r *CleanResponse) setMeta(m *ResponseMeta)     { r.Meta = m }
+
 type errorResponse struct {
 	Success bool   `json:"success"`
 	Code    int    `json:"code"`
@@ -0,0 +1,24 @@
+package dmweb
+
+import (
+	"fmt"
+	"regexp"
+	"unicode"
+)
+
+// developerIDPattern matches a Talk2M developer ID, a UUID.
+var developerIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// validateCredentials checks the format of the account and developer
+// IDs, see WithStrictValidation.
+func validateCredentials(accountID, developerID string) error {
+	for _, r := range accountID {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return fmt.Errorf("%w: accountID %q contains whitespace or control characters", ErrInvalidCredentials, accountID)
+		}
+	}
+	if !developerIDPattern.MatchString(developerID) {
+		return fmt.Errorf("%w: developerID %q is not a UUID", ErrInvalidCredentials, developerID)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package dmweb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// flexFloat is a float64 that some firmware versions write as a string,
+// e.g. "1510".
+type flexFloat float64
+
+// UnmarshalJSON decodes a number, or a string holding a number.
+func (f *flexFloat) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && (b[0] == '-' || b[0] >= '0' && b[0] <= '9') {
+		// The decoder only passes valid JSON, so this is a JSON number,
+		// whose syntax ParseFloat accepts.
+		v, err := strconv.ParseFloat(string(b), 64)
+		if err != nil {
+			return err
+		}
+		*f = flexFloat(v)
+		return nil
+	}
+	if len(b) == 0 || b[0] != '"' {
+		return json.Unmarshal(b, (*float64)(f))
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return fmt.Errorf("value %q is not a number", s)
+	}
+	*f = flexFloat(v)
+	return nil
+}
+
+// The *Alias types have the fields of the response types without their
+// methods, to decode them without recursing into UnmarshalJSON.
+type (
+	tagAlias          Tag
+	dataTagAlias      DataTag
+	historyPointAlias HistoryPoint
+)
+
+// isStringValueError reports whether err is the error of decoding a
+// string value into a float64 value field.
+func isStringValueError(err error) bool {
+	var te *json.UnmarshalTypeError
+	return errors.As(err, &te) && te.Value == "string" && te.Type.Kind() == reflect.Float64 &&
+		(te.Field == "value" || strings.HasSuffix(te.Field, ".value"))
+}
+
+// UnmarshalJSON decodes a tag, accepting a value written as a number or
+// as a string holding a number.
+func (t *Tag) UnmarshalJSON(b []byte) error {
+	err := json.Unmarshal(b, (*tagAlias)(t))
+	if !isStringValueError(err) {
+		return err
+	}
+	v := struct {
+		*tagAlias
+		Value flexFloat `json:"value"`
+	}{tagAlias: (*tagAlias)(t)}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	t.Value = float64(v.Value)
+	return nil
+}
+
+// UnmarshalJSON is like Tag.UnmarshalJSON.
+func (t *DataTag) UnmarshalJSON(b []byte) error {
+	err := json.Unmarshal(b, (*dataTagAlias)(t))
+	if !isStringValueError(err) {
+		return err
+	}
+	v := struct {
+		*dataTagAlias
+		Value flexFloat `json:"value"`
+	}{dataTagAlias: (*dataTagAlias)(t)}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	t.Value = float64(v.Value)
+	return nil
+}
+
+// UnmarshalJSON is like Tag.UnmarshalJSON.
+func (p *HistoryPoint) UnmarshalJSON(b []byte) error {
+	err := json.Unmarshal(b, (*historyPointAlias)(p))
+	if !isStringValueError(err) {
+		return err
+	}
+	v := struct {
+		*historyPointAlias
+		Value flexFloat `json:"value"`
+	}{historyPointAlias: (*historyPointAlias)(p)}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	p.Value = float64(v.Value)
+	return nil
+}
+
+// The flex* types mirror the responses holding tags, with the fields of
+// the tags and history points but not their UnmarshalJSON methods, whose
+// decoding a json.Decoder can't do in the same pass, nor pass
+// DisallowUnknownFields to. The client decodes responses into them, see
+// flexDecoder, keeping string values and WithStrictDecoding as fast as
+// decoding numbers.
+type (
+	flexTag struct {
+		tagAlias
+		Value flexFloat `json:"value"`
+	}
+	flexHistoryPoint struct {
+		historyPointAlias
+		Value flexFloat `json:"value"`
+	}
+	flexDataTag struct {
+		dataTagAlias
+		Value   flexFloat          `json:"value"`
+		History []flexHistoryPoint `json:"history"`
+	}
+	flexEwon struct {
+		Ewon
+		Tags []flexTag `json:"tags"`
+	}
+	flexDataEwon struct {
+		DataEwon
+		Tags []flexDataTag `json:"tags"`
+	}
+	flexEwonResponse struct {
+		Success bool `json:"success"`
+		flexEwon
+	}
+	flexEwonsResponse struct {
+		Success bool       `json:"success"`
+		Ewons   []flexEwon `json:"ewons"`
+	}
+	flexGetDataResponse struct {
+		GetDataResponse
+		Ewons []flexDataEwon `json:"ewons"`
+	}
+	flexSyncResponse struct {
+		SyncResponse
+		Ewons []flexDataEwon `json:"ewons"`
+	}
+)
+
+// flexDecoder is implemented by the responses holding tags, which the
+// client decodes through their flex* mirror.
+type flexDecoder interface {
+	// flexMirror returns a new mirror to decode the response into.
+	flexMirror() interface{}
+	// setFlex sets the response from its decoded mirror m, keeping its
+	// ResponseMeta.
+	setFlex(m interface{})
+}
+
+func (t *flexTag) tag() *Tag {
+	out := Tag(t.tagAlias)
+	out.Value = float64(t.Value)
+	return &out
+}
+
+func (e *flexEwon) ewon() Ewon {
+	out := e.Ewon
+	out.Tags = nil
+	if e.Tags != nil {
+		out.Tags = make(Tags, len(e.Tags))
+		for i := range e.Tags {
+			out.Tags[i] = e.Tags[i].tag()
+		}
+	}
+	return out
+}
+
+func flexDataEwons(es []flexDataEwon) []DataEwon {
+	if es == nil {
+		return nil
+	}
+	out := make([]DataEwon, len(es))
+	for i, e := range es {
+		out[i] = e.DataEwon
+		out[i].Tags = nil
+		if e.Tags == nil {
+			continue
+		}
+		out[i].Tags = make([]DataTag, len(e.Tags))
+		for j, t := range e.Tags {
+			ot := &out[i].Tags[j]
+			*ot = DataTag(t.dataTagAlias)
+			ot.Value = float64(t.Value)
+			ot.History = nil
+			if t.History == nil {
+				continue
+			}
+			ot.History = make([]HistoryPoint, len(t.History))
+			for k, h := range t.History {
+				ot.History[k] = HistoryPoint(h.historyPointAlias)
+				ot.History[k].Value = float64(h.Value)
+			}
+		}
+	}
+	return out
+}
+
+func (r *ewonResponse) flexMirror() interface{} { return new(flexEwonResponse) }
+
+func (r *ewonResponse) setFlex(m interface{}) {
+	fr := m.(*flexEwonResponse)
+	r.Success = fr.Success
+	r.Ewon = fr.ewon()
+}
+
+func (r *ewonsResponse) flexMirror() interface{} { return new(flexEwonsResponse) }
+
+func (r *ewonsResponse) setFlex(m interface{}) {
+	fr := m.(*flexEwonsResponse)
+	r.Success = fr.Success
+	r.Ewons = nil
+	if fr.Ewons != nil {
+		r.Ewons = make(Ewons, len(fr.Ewons))
+		for i := range fr.Ewons {
+			e := fr.Ewons[i].ewon()
+			r.Ewons[i] = &e
+		}
+	}
+}
+
+func (d *GetDataResponse) flexMirror() interface{} { return new(flexGetDataResponse) }
+
+func (d *GetDataResponse) setFlex(m interface{}) {
+	fd := m.(*flexGetDataResponse)
+	meta := d.Meta
+	*d = fd.GetDataResponse
+	d.Meta = meta
+	d.Ewons = flexDataEwons(fd.Ewons)
+}
+
+func (s *SyncResponse) flexMirror() interface{} { return new(flexSyncResponse) }
+
+func (s *SyncResponse) setFlex(m interface{}) {
+	fs := m.(*flexSyncResponse)
+	meta := s.Meta
+	*s = fs.SyncResponse
+	s.Meta = meta
+	s.Ewons = flexDataEwons(fs.Ewons)
+}
@@ -0,0 +1,66 @@
+package dmweb
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringValues(t *testing.T) {
+	var tag Tag
+	assert.NoError(t, json.Unmarshal([]byte(`{"id":10,"name":"A","value":"1510","quality":"good"}`), &tag))
+	assert.Equal(t, 1510.0, tag.Value)
+	assert.Equal(t, QualityGood, tag.Quality)
+	assert.NoError(t, json.Unmarshal([]byte(`{"id":10,"value":1.5}`), &tag))
+	assert.Equal(t, 1.5, tag.Value)
+	assert.EqualError(t, json.Unmarshal([]byte(`{"value":"on"}`), &tag), `value "on" is not a number`)
+
+	var d DataTag
+	assert.NoError(t, json.Unmarshal([]byte(`{"id":10,"value":"2","history":[{"value":"1.5"},{"value":3}]}`), &d))
+	assert.Equal(t, 2.0, d.Value)
+	if assert.Len(t, d.History, 2) {
+		assert.Equal(t, 1.5, d.History[0].Value)
+		assert.Equal(t, 3.0, d.History[1].Value)
+	}
+
+	h := NewTestClient(func(req *http.Request) *http.Response {
+		return jsonResponse(200, `{"success":true,"transactionId":"1","ewons":[{"id":1,"name":"Ewon1","tags":[
+			{"id":10,"name":"A","value":"9007199254740993","history":[
+				{"date":"2018-11-08T14:17:58Z","value":"21.5"},
+				{"date":"2018-11-08T14:18:58Z","value":22}]}]}]}`)
+	})
+	c, err := New(h, "aid", "username", "password", "devid", WithUseNumber(), WithStrictDecoding())
+	assert.NoError(t, err)
+	s, err := c.FirstSyncData()
+	assert.NoError(t, err)
+	dt := s.Ewons[0].Tags[0]
+	v, err := dt.Int64Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), v)
+	assert.Equal(t, 21.5, dt.History[0].Value)
+	assert.Equal(t, 22.0, dt.History[1].Value)
+}
+
+func TestStrictDecodingTags(t *testing.T) {
+	body := `{"success":true,"transactionId":"1","ewons":[{"id":1,"name":"Ewon1","tags":[
+		{"id":10,"name":"A","value":1,"history":[{"date":"2018-11-08T14:17:58Z","value":1,"newField":1}]}]}]}`
+	h := NewTestClient(func(req *http.Request) *http.Response {
+		return jsonResponse(200, body)
+	})
+	c, err := New(h, "aid", "username", "password", "devid")
+	assert.NoError(t, err)
+	s, err := c.FirstSyncData()
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, s.Ewons[0].Tags[0].History[0].Value)
+
+	c, err = New(h, "aid", "username", "password", "devid", WithStrictDecoding())
+	assert.NoError(t, err)
+	_, err = c.FirstSyncData()
+	assert.EqualError(t, err, `json: unknown field "newField"`)
+
+	body = `{"success":true,"ewons":[{"id":1,"name":"Ewon1","tags":[{"id":10,"newTagField":"x"}]}]}`
+	_, err = c.GetEwons()
+	assert.EqualError(t, err, `json: unknown field "newTagField"`)
+}
@@ -0,0 +1,95 @@
+package dmweb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GetDataByWindows gets the history between from and to by splitting
+// the range into consecutive windows of the given length, requested one
+// after the other with getdata and merged into a single response, like
+// GetDataAll. A window for which the DataMailbox reports more data
+// available is split in half, down to a second, below which it is paged
+// with GetDataAll. Points on the boundary between two windows, which
+// both windows return, are kept once. An ewonID of 0 gets the history of
+// all eWONs.
+// Unlike syncdata, which returns all new data of the account once, the
+// windows can be requested again and their size bounds every response
+// without relying on the limit of the DataMailbox, at the cost of a
+// request per window, including empty ones. Windows should therefore be
+// sized to the logging rate of the tags. On error, the data received so
+// far is returned along with it.
+func (c *Client) GetDataByWindows(ctx context.Context, ewonID int, from, to time.Time, window time.Duration) (*GetDataResponse, error) {
+	if window < time.Second {
+		return nil, errors.New("window must be at least a second")
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("from %s is after to %s", from, to)
+	}
+	out := &GetDataResponse{Success: true}
+	w := &windowGetter{c: c, ewonID: ewonID, out: out, m: newDataMerger(out)}
+	for start := from; ; {
+		end := start.Add(window)
+		if end.After(to) {
+			end = to
+		}
+		if err := w.get(ctx, start, end); err != nil {
+			return out, err
+		}
+		if !end.Before(to) {
+			return out, nil
+		}
+		start = end
+	}
+}
+
+// windowGetter gets the windows of GetDataByWindows.
+type windowGetter struct {
+	c      *Client
+	ewonID int
+	out    *GetDataResponse
+	m      *dataMerger
+	// boundary holds the points at the end of the previous window, which
+	// the next window returns again.
+	boundary map[recordKey]bool
+}
+
+// get gets the window from start to end, splitting it while more data
+// is available.
+func (w *windowGetter) get(ctx context.Context, start, end time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	params := GetDataParams{From: &start, To: &end}
+	if w.ewonID != 0 {
+		params.EwonID = &w.ewonID
+	}
+	d, err := w.c.GetDataWithParamsContext(ctx, params)
+	if err != nil {
+		return err
+	}
+	if d.MoreDataAvailable {
+		if half := (end.Sub(start) / 2).Truncate(time.Second); half >= time.Second {
+			mid := start.Add(half)
+			if err := w.get(ctx, start, mid); err != nil {
+				return err
+			}
+			return w.get(ctx, mid, end)
+		}
+		if d, err = w.c.GetDataAll(ctx, params); err != nil {
+			w.m.add(d.Ewons, w.boundary)
+			return err
+		}
+	}
+	w.out.Meta = d.Meta
+	w.m.add(d.Ewons, w.boundary)
+	w.boundary = make(map[recordKey]bool)
+	for _, r := range records(d.Ewons) {
+		if r.rawDate().Equal(end) {
+			w.boundary[r.key()] = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package dmweb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDataByWindows(t *testing.T) {
+	var windows []string
+	c := newTestDMWebClient(func(req *http.Request) *http.Response {
+		q := req.URL.Query()
+		assert.Equal(t, "1", q.Get("ewonId"))
+		w := q.Get("from") + "/" + q.Get("to")
+		windows = append(windows, w)
+		switch w {
+		case "2020-01-01T00:00:00Z/2020-01-01T01:00:00Z":
+			return jsonResponse(200, `{"success":true,"ewons":[{"id":1,"name":"Ewon1","tags":[
+				{"id":10,"name":"A","history":[{"date":"2020-01-01T00:00:00Z","value":1},{"date":"2020-01-01T01:00:00Z","value":2}]}]}]}`)
+		case "2020-01-01T01:00:00Z/2020-01-01T02:00:00Z":
+			return jsonResponse(200, `{"success":true,"moreDataAvailable":true,"ewons":[]}`)
+		case "2020-01-01T01:00:00Z/2020-01-01T01:30:00Z":
+			return jsonResponse(200, `{"success":true,"ewons":[{"id":1,"name":"Ewon1","tags":[
+				{"id":10,"name":"A","history":[{"date":"2020-01-01T01:00:00Z","value":2},{"date":"2020-01-01T01:10:00Z","value":3}]}]}]}`)
+		case "2020-01-01T01:30:00Z/2020-01-01T02:00:00Z":
+			return jsonResponse(200, `{"success":true,"ewons":[{"id":1,"name":"Ewon1","tags":[
+				{"id":11,"name":"B","history":[{"date":"2020-01-01T01:40:00Z","value":4}]}]}]}`)
+		case "2020-01-01T02:00:00Z/2020-01-01T02:30:00Z":
+			return jsonResponse(200, `{"success":true,"ewons":[]}`)
+		}
+		t.Fatalf("unexpected window %q", w)
+		return nil
+	})
+
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := c.GetDataByWindows(context.Background(), 1, from, from.Add(150*time.Minute), time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"2020-01-01T00:00:00Z/2020-01-01T01:00:00Z",
+		"2020-01-01T01:00:00Z/2020-01-01T02:00:00Z",
+		"2020-01-01T01:00:00Z/2020-01-01T01:30:00Z",
+		"2020-01-01T01:30:00Z/2020-01-01T02:00:00Z",
+		"2020-01-01T02:00:00Z/2020-01-01T02:30:00Z",
+	}, windows)
+	if assert.Len(t, d.Ewons, 1) && assert.Len(t, d.Ewons[0].Tags, 2) {
+		var values []float64
+		for _, p := range d.Ewons[0].Tags[0].History {
+			values = append(values, p.Value)
+		}
+		assert.Equal(t, []float64{1, 2, 3}, values)
+		assert.Len(t, d.Ewons[0].Tags[1].History, 1)
+	}
+
+	_, err = c.GetDataByWindows(context.Background(), 1, from, from.Add(time.Hour), time.Millisecond)
+	assert.EqualError(t, err, "window must be at least a second")
+}